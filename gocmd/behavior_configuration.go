@@ -0,0 +1,69 @@
+package main
+
+const (
+	defaultJumpLines      = 10
+	defaultSuperJumpLines = 50
+	// The number of items to move through when paging in the quick open menu.
+	defaultQuickOpenPageSize = 5
+	// The time (in milliseconds) to wait between the setup and execution parts
+	// of the groog.testFile command.
+	defaultTestFileDelay = 25
+)
+
+func jumpLinesSchema() *JSONSchema {
+	return NewJSONInteger(
+		JSONDescription("Number of lines to move with groog.jump and groog.fall."),
+		JSONDefault(defaultJumpLines),
+		JSONMinimum(1),
+	)
+}
+
+func superJumpLinesSchema() *JSONSchema {
+	return NewJSONInteger(
+		JSONMarkdownDescription("Number of lines to move with groog.jump and groog.fall when the `superJump` argument is set (e.g. `ctrl+shift+l`)."),
+		JSONDefault(defaultSuperJumpLines),
+		JSONMinimum(1),
+	)
+}
+
+func quickOpenPageSizeSchema() *JSONSchema {
+	return NewJSONInteger(
+		JSONDescription("Number of items to move through when paging up or down in the quick open menu."),
+		JSONDefault(defaultQuickOpenPageSize),
+		JSONMinimum(1),
+	)
+}
+
+func testFileDelaySchema() *JSONSchema {
+	return NewJSONInteger(
+		JSONDescription("Delay (in milliseconds) between clearing the terminal and running the test command when testing the current file."),
+		JSONDefault(defaultTestFileDelay),
+		JSONMinimum(0),
+	)
+}
+
+func editorSettingsSchema() *JSONSchema {
+	return NewJSONObject(
+		nil,
+		JSONMarkdownDescription("Map from `editor.*` setting name to the value that the `groog.updateSettings` command sets it to."),
+		JSONDefault(map[string]interface{}{
+			"autoClosingQuotes": "never",
+			// My preference is to only auto-close curly brackets, but this auto-closes (), [], and {}.
+			// So, we disable this, and manually implement auto-close for curly brackets ourselves.
+			// See keybindings.json behavior for the "{" character for implementation details.
+			"autoClosingBrackets": "never",
+			"codeActionsOnSave": map[string]interface{}{
+				"source.organizeImports": true,
+				"source.fixAll.eslint":   true,
+			},
+			"cursorSurroundingLines": 6,
+			"detectIndentation":      true,
+			"insertSpaces":           true,
+			"rulers":                 []int{80, 200},
+			"tabSize":                2,
+			"tokenColorCustomizations": map[string]interface{}{
+				"keywords": "#d389d3",
+			},
+		}),
+	)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// See the following link for the changelog format:
+// https://keepachangelog.com
+
+const (
+	changelogUnreleasedHeader = "## [Unreleased]"
+)
+
+// changelogSection returns a new changelog section for the version, seeded
+// with the contribution changes since the previous version.
+func changelogSection(version *Version, date time.Time, d *contributionDiff) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## [%s] - %s\n", version, date.Format("2006-01-02"))
+
+	if d.empty() {
+		sb.WriteString("\n- No contribution changes\n")
+		return sb.String()
+	}
+
+	var added, removed, changed []string
+	for _, c := range d.AddedCommands {
+		added = append(added, fmt.Sprintf("Command `%s` (%s)", c.Command, c.Title))
+	}
+	for _, kb := range d.AddedKeybindings {
+		added = append(added, fmt.Sprintf("Keybinding %s", keybindingString(kb)))
+	}
+	for _, s := range d.AddedSettings {
+		added = append(added, fmt.Sprintf("Setting `%s`", s))
+	}
+	for _, c := range d.RemovedCommands {
+		removed = append(removed, fmt.Sprintf("Command `%s` (%s)", c.Command, c.Title))
+	}
+	for _, kb := range d.RemovedKeybindings {
+		removed = append(removed, fmt.Sprintf("Keybinding %s", keybindingString(kb)))
+	}
+	for _, s := range d.RemovedSettings {
+		removed = append(removed, fmt.Sprintf("Setting `%s`", s))
+	}
+
+	for _, c := range d.RetitledCommands {
+		changed = append(changed, fmt.Sprintf("Command `%s` renamed from %q to %q", c.Command, c.OldTitle, c.NewTitle))
+	}
+	for _, ckb := range d.ChangedKeybindings {
+		changed = append(changed, fmt.Sprintf("Keybinding %s", ckb))
+	}
+	for _, cs := range d.ChangedSettings {
+		changed = append(changed, fmt.Sprintf("Setting `%s` (%s)", cs.Name, strings.Join(cs.Fields, ", ")))
+	}
+	for _, s := range d.AddedSnippetFiles {
+		added = append(added, fmt.Sprintf("Snippets `%s` (%s)", s.Path, s.Language))
+	}
+	for _, s := range d.RemovedSnippetFiles {
+		removed = append(removed, fmt.Sprintf("Snippets `%s` (%s)", s.Path, s.Language))
+	}
+	for _, s := range d.ChangedSnippets {
+		changed = append(changed, fmt.Sprintf("Snippets %s", s))
+	}
+
+	for _, group := range []struct {
+		title string
+		items []string
+	}{
+		{"Added", added},
+		{"Changed", changed},
+		{"Removed", removed},
+	} {
+		if len(group.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n### %s\n\n", group.title)
+		for _, item := range group.items {
+			fmt.Fprintf(&sb, "- %s\n", item)
+		}
+	}
+	return sb.String()
+}
+
+// insertChangelogSection adds the section to the changelog contents. The
+// section is placed before the most recent version's section (i.e. after the
+// `Unreleased` section, if there is one).
+func insertChangelogSection(contents, section string) string {
+	lines := strings.Split(contents, "\n")
+	insertAt := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") && !strings.HasPrefix(line, changelogUnreleasedHeader) {
+			insertAt = i
+			break
+		}
+	}
+
+	// Trim trailing blank lines so sections are separated by exactly one blank line.
+	before := strings.TrimRight(strings.Join(lines[:insertAt], "\n"), "\n")
+	after := strings.Join(lines[insertAt:], "\n")
+	r := fmt.Sprintf("%s\n\n%s", before, section)
+	if strings.TrimSpace(after) != "" {
+		r = fmt.Sprintf("%s\n%s", r, after)
+	}
+	return r
+}
+
+// updateChangelog inserts the section into the changelog file.
+func updateChangelog(filename, section string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read changelog: %v", err)
+	}
+
+	contents := string(b)
+	if strings.TrimSpace(contents) == "" {
+		contents = "# Change Log\n"
+	}
+
+	if err := os.WriteFile(filename, []byte(insertChangelogSection(contents, section)), 0644); err != nil {
+		return fmt.Errorf("failed to write changelog: %v", err)
+	}
+	return nil
+}
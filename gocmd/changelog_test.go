@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChangelogSection(t *testing.T) {
+	date := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name string
+		diff *contributionDiff
+		want string
+	}{
+		{
+			name: "no changes",
+			diff: &contributionDiff{},
+			want: "## [1.2.3] - 2024-03-05\n\n- No contribution changes\n",
+		},
+		{
+			name: "grouped changes",
+			diff: &contributionDiff{
+				AddedCommands:    []*Command{{"groog.new", "New"}},
+				AddedKeybindings: []*Keybinding{{Key: "ctrl+k", Command: "groog.new"}},
+				RetitledCommands: []*retitledCommand{{"groog.old", "Old", "Older"}},
+				ChangedSettings:  []*changedSetting{{"groog.jump.lines", []string{"default"}}},
+				RemovedSettings:  []string{"groog.gone"},
+				AddedSnippetFiles: []*Snippet{
+					{Path: "snippets/go.json", Language: "go"},
+				},
+			},
+			want: "## [1.2.3] - 2024-03-05\n" +
+				"\n### Added\n\n" +
+				"- Command `groog.new` (New)\n" +
+				"- Keybinding `ctrl+k` → `groog.new`\n" +
+				"- Snippets `snippets/go.json` (go)\n" +
+				"\n### Changed\n\n" +
+				"- Command `groog.old` renamed from \"Old\" to \"Older\"\n" +
+				"- Setting `groog.jump.lines` (default)\n" +
+				"\n### Removed\n\n" +
+				"- Setting `groog.gone`\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := parseVersion("1.2.3")
+			if err != nil {
+				t.Fatalf("parseVersion() returned error: %v", err)
+			}
+			if got := changelogSection(v, date, test.diff); got != test.want {
+				t.Errorf("changelogSection() returned:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestInsertChangelogSection(t *testing.T) {
+	section := "## [1.1.0] - 2024-03-05\n\n- New\n"
+	for _, test := range []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{
+			name:     "empty changelog",
+			contents: "# Change Log\n",
+			want:     "# Change Log\n\n## [1.1.0] - 2024-03-05\n\n- New\n",
+		},
+		{
+			name:     "before previous version",
+			contents: "# Change Log\n\n## [1.0.0] - 2024-01-01\n\n- Old\n",
+			want:     "# Change Log\n\n## [1.1.0] - 2024-03-05\n\n- New\n\n## [1.0.0] - 2024-01-01\n\n- Old\n",
+		},
+		{
+			name:     "after unreleased section",
+			contents: "# Change Log\n\n## [Unreleased]\n\n- Pending\n\n\n## [1.0.0] - 2024-01-01\n\n- Old\n",
+			want:     "# Change Log\n\n## [Unreleased]\n\n- Pending\n\n## [1.1.0] - 2024-03-05\n\n- New\n\n## [1.0.0] - 2024-01-01\n\n- Old\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := insertChangelogSection(test.contents, section); got != test.want {
+				t.Errorf("insertChangelogSection() returned:\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUpdateChangelog(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := updateChangelog(filename, "## [1.0.0] - 2024-01-01\n"); err != nil {
+		t.Fatalf("updateChangelog() returned error: %v", err)
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read changelog: %v", err)
+	}
+	if want := "# Change Log\n\n## [1.0.0] - 2024-01-01\n"; string(b) != want {
+		t.Errorf("updateChangelog() wrote %q; want %q", b, want)
+	}
+}
@@ -1,10 +1,19 @@
 package main
 
+import (
+	"fmt"
+
+	"golang.org/x/exp/slices"
+)
+
 // See this link for more details:
 // https://code.visualstudio.com/api/references/contribution-points#contributes.configuration
 
 type JSONSchemaType interface {
 	ToJSONSchema() map[string]interface{}
+	// validate returns a list of errors describing how the provided value
+	// (in json.Unmarshal form) does not conform to the schema type.
+	validate(path string, v interface{}) []string
 }
 
 type JSONSchema struct {
@@ -30,6 +39,10 @@ func NewJSONString(opts ...JSONSchemaOption) *JSONSchema {
 	return NewJSONSchema(&JSONSchemaSimpleType{"string"}, opts...)
 }
 
+func NewJSONInteger(opts ...JSONSchemaOption) *JSONSchema {
+	return NewJSONSchema(&JSONSchemaSimpleType{"integer"}, opts...)
+}
+
 func NewJSONBool(opts ...JSONSchemaOption) *JSONSchema {
 	return NewJSONSchema(&JSONSchemaSimpleType{"boolean"}, opts...)
 }
@@ -46,18 +59,158 @@ func (s *JSONSchema) evaluate() map[string]interface{} {
 	return r
 }
 
-func groogConfiguration() *Configuration {
-	return &Configuration{
-		"Groog",
-		map[string]map[string]interface{}{
-			"groog.typos":    typosSchema().evaluate(),
-			"gopls.analyses": goplsSchema().evaluate(),
+// ConfigurationSection is a titled category of settings. Each section is
+// contributed as a separate entry in the `contributes.configuration` array.
+type ConfigurationSection struct {
+	Title      string
+	Properties []*ConfigurationProperty
+}
+
+type ConfigurationProperty struct {
+	Name   string
+	Schema *JSONSchema
+}
+
+// configProperty returns a property that is displayed at the provided
+// position in its section.
+func configProperty(name string, order int, schema *JSONSchema) *ConfigurationProperty {
+	schema.Options["order"] = order
+	return &ConfigurationProperty{name, schema}
+}
+
+// groogConfigurationSections returns all configuration sections in the order
+// in which they should be displayed in the settings UI.
+func groogConfigurationSections() ([]*ConfigurationSection, error) {
+	globalCorrections, _, err := splitDefaultCorrections()
+	if err != nil {
+		return nil, err
+	}
+
+	return []*ConfigurationSection{
+		{
+			Title: "Typos",
+			Properties: []*ConfigurationProperty{
+				configProperty("groog.typos", 1, typosSchema(JSONDefault(globalCorrections))),
+				configProperty("groog.includeDefaultTypos", 2, includeDefaultTyposSchema()),
+			},
 		},
+		{
+			Title: "Navigation",
+			Properties: []*ConfigurationProperty{
+				configProperty("groog.jump.lines", 1, jumpLinesSchema()),
+				configProperty("groog.jump.superJumpLines", 2, superJumpLinesSchema()),
+				configProperty("groog.quickOpen.pageSize", 3, quickOpenPageSizeSchema()),
+			},
+		},
+		{
+			// The test file delay is the delay between clearing the terminal and
+			// running the test command in it.
+			Title: "Terminal",
+			Properties: []*ConfigurationProperty{
+				configProperty("groog.testFile.delay", 1, testFileDelaySchema()),
+			},
+		},
+		{
+			Title: "Settings",
+			Properties: []*ConfigurationProperty{
+				configProperty("groog.editorSettings", 1, editorSettingsSchema()),
+			},
+		},
+		{
+			Title: "Other",
+			Properties: []*ConfigurationProperty{
+				configProperty("gopls.analyses", 1, goplsSchema()),
+			},
+		},
+	}, nil
+}
+
+func groogConfigurationProperties() (map[string]*JSONSchema, error) {
+	sections, err := groogConfigurationSections()
+	if err != nil {
+		return nil, err
 	}
+
+	props := map[string]*JSONSchema{}
+	for _, section := range sections {
+		for _, prop := range section.Properties {
+			props[prop.Name] = prop.Schema
+		}
+	}
+	return props, nil
+}
+
+func groogConfiguration() ([]*Configuration, error) {
+	sections, err := groogConfigurationSections()
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []*Configuration
+	for _, section := range sections {
+		if len(section.Properties) == 0 {
+			return nil, fmt.Errorf("configuration section %q has no properties", section.Title)
+		}
+		configs = append(configs, section.configuration(len(configs)+1))
+	}
+	return configs, nil
+}
+
+// groogConfigurationDefaults returns the language-scoped default setting values.
+func groogConfigurationDefaults() (map[string]map[string]interface{}, error) {
+	_, languageCorrections, err := splitDefaultCorrections()
+	if err != nil {
+		return nil, err
+	}
+	return typosConfigurationDefaults(languageCorrections), nil
+}
+
+// configuration evaluates the section. Properties that don't explicitly set an
+// order (via JSONOrder) are placed after the explicitly ordered ones, in the
+// order in which they are listed in the section.
+func (cs *ConfigurationSection) configuration(order int) *Configuration {
+	props := slices.Clone(cs.Properties)
+	slices.SortStableFunc(props, func(a, b *ConfigurationProperty) int {
+		ao, aOk := a.Schema.order()
+		bo, bOk := b.Schema.order()
+		switch {
+		case aOk && bOk:
+			return ao - bo
+		case aOk:
+			return -1
+		case bOk:
+			return 1
+		}
+		return 0
+	})
+
+	m := map[string]map[string]interface{}{}
+	var next int
+	for _, prop := range props {
+		v := prop.Schema.evaluate()
+		if o, ok := prop.Schema.order(); ok {
+			next = o
+		} else {
+			v["order"] = next
+		}
+		next++
+		m[prop.Name] = v
+	}
+	return &Configuration{
+		Title:      cs.Title,
+		Order:      order,
+		Properties: m,
+	}
+}
+
+func (s *JSONSchema) order() (int, bool) {
+	o, ok := s.Options["order"].(int)
+	return o, ok
 }
 
 type Configuration struct {
 	Title      string                            `json:"title"`
+	Order      int                               `json:"order"`
 	Properties map[string]map[string]interface{} `json:"properties"`
 }
 
@@ -81,6 +234,21 @@ func JSONDefault(v interface{}) JSONSchemaOption {
 	}
 }
 
+// JSONScope sets the scope in which the setting can be configured. See the
+// following link for valid values:
+// https://code.visualstudio.com/api/references/contribution-points#Configuration-property-schema
+func JSONScope(scope string) JSONSchemaOption {
+	return map[string]interface{}{
+		"scope": scope,
+	}
+}
+
+func JSONMinimum(min int) JSONSchemaOption {
+	return map[string]interface{}{
+		"minimum": min,
+	}
+}
+
 func JSONOrder(order int) JSONSchemaOption {
 	return map[string]interface{}{
 		"order": order,
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// validateConfiguration verifies that every default value in the contributed
+// configuration (including language-scoped defaults) conforms to the schema it
+// is attached to.
+func validateConfiguration() error {
+	props, err := groogConfigurationProperties()
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	names := maps.Keys(props)
+	sort.Strings(names)
+	for _, name := range names {
+		errs = append(errs, props[name].validateDefaults(name)...)
+	}
+
+	// Validate the language-scoped defaults against the property's schema.
+	langDefaults, err := groogConfigurationDefaults()
+	if err != nil {
+		return err
+	}
+	langs := maps.Keys(langDefaults)
+	sort.Strings(langs)
+	for _, lang := range langs {
+		settings := langDefaults[lang]
+		settingNames := maps.Keys(settings)
+		sort.Strings(settingNames)
+		for _, name := range settingNames {
+			path := fmt.Sprintf("%s.%s", lang, name)
+			schema, ok := props[name]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s: no schema for setting", path))
+				continue
+			}
+			if schema.Options["scope"] != "language-overridable" {
+				errs = append(errs, fmt.Sprintf("%s: setting is not language-overridable", path))
+				continue
+			}
+			v, err := normalizeJSON(settings[name])
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: value is not valid json: %v", path, err))
+				continue
+			}
+			errs = append(errs, schema.validate(path, v)...)
+		}
+	}
+	return validationError("configuration", errs)
+}
+
+// validateJSONFile verifies that the contents of the provided JSON file
+// conform to the provided schema.
+func validateJSONFile(schema *JSONSchema, filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %v", err)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %v", filename, err)
+	}
+	return validationError(filename, schema.validate(filename, v))
+}
+
+// duplicateJSONKeys returns the paths of the object keys that are defined more
+// than once in the json contents (json.Unmarshal silently keeps the last one).
+func duplicateJSONKeys(path string, b []byte) ([]string, error) {
+	var dups []string
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	var walk func(path string) error
+	walk = func(path string) error {
+		t, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'):
+			seen := map[string]bool{}
+			for decoder.More() {
+				kt, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				k := kt.(string)
+				if seen[k] {
+					dups = append(dups, fmt.Sprintf("%s.%s", path, k))
+				}
+				seen[k] = true
+				if err := walk(fmt.Sprintf("%s.%s", path, k)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		case json.Delim('['):
+			for i := 0; decoder.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		}
+		return nil
+	}
+
+	if err := walk(path); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return dups, nil
+}
+
+func validationError(name string, errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s failed schema validation:\n%s", name, strings.Join(errs, "\n"))
+}
+
+// validateDefaults checks the schema's default value (and the default values
+// of all nested schemas) against the schema it is defined on.
+func (s *JSONSchema) validateDefaults(path string) []string {
+	var errs []string
+	if d, ok := s.Options["default"]; ok {
+		v, err := normalizeJSON(d)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: default value is not valid json: %v", path, err))
+		} else {
+			errs = append(errs, s.validate(fmt.Sprintf("%s (default)", path), v)...)
+		}
+	}
+
+	switch t := s.SchemaType.(type) {
+	case *JSONSchemaArray:
+		errs = append(errs, t.items.validateDefaults(fmt.Sprintf("%s[]", path))...)
+	case *JSONSchemaObject:
+		keys := maps.Keys(t.properties)
+		sort.Strings(keys)
+		for _, k := range keys {
+			errs = append(errs, t.properties[k].validateDefaults(fmt.Sprintf("%s.%s", path, k))...)
+		}
+	}
+	return errs
+}
+
+// validate checks that the provided value conforms to the schema. The value
+// must be in the form produced by json.Unmarshal (see normalizeJSON).
+func (s *JSONSchema) validate(path string, v interface{}) []string {
+	errs := s.SchemaType.validate(path, v)
+	if minimum, ok := s.Options["minimum"]; ok {
+		// Normalize so integer and float minimums are both float64.
+		m, err := normalizeJSON(minimum)
+		min, isNumber := m.(float64)
+		switch {
+		case err != nil || !isNumber:
+			errs = append(errs, fmt.Sprintf("%s: minimum (%v) is not a number", path, minimum))
+		default:
+			if f, ok := v.(float64); ok && f < min {
+				errs = append(errs, fmt.Sprintf("%s: value %v is less than the minimum (%v)", path, v, min))
+			}
+		}
+	}
+	if enum, ok := s.Options["enum"]; ok {
+		normalized, err := normalizeJSON(enum)
+		if err != nil {
+			return append(errs, fmt.Sprintf("%s: enum is not valid json: %v", path, err))
+		}
+		values, ok := normalized.([]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: enum (%v) is not a list", path, enum))
+		}
+		var found bool
+		for _, ev := range values {
+			if jsonEqual(ev, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s: value %v is not one of the allowed enum values %v", path, v, values))
+		}
+	}
+	return errs
+}
+
+func (a *JSONSchemaArray) validate(path string, v interface{}) []string {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return []string{typeMismatch(path, "array", v)}
+	}
+
+	var errs []string
+	for i, item := range arr {
+		errs = append(errs, a.items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+	}
+	return errs
+}
+
+func (s *JSONSchemaSimpleType) validate(path string, v interface{}) []string {
+	var ok bool
+	switch s.type_ {
+	case "string":
+		_, ok = v.(string)
+	case "boolean":
+		_, ok = v.(bool)
+	case "number":
+		_, ok = v.(float64)
+	case "integer":
+		var f float64
+		f, ok = v.(float64)
+		ok = ok && f == math.Trunc(f)
+	default:
+		return []string{fmt.Sprintf("%s: unknown schema type %q", path, s.type_)}
+	}
+
+	if !ok {
+		return []string{typeMismatch(path, s.type_, v)}
+	}
+	return nil
+}
+
+func (o *JSONSchemaObject) validate(path string, v interface{}) []string {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return []string{typeMismatch(path, "object", v)}
+	}
+
+	var errs []string
+	keys := maps.Keys(m)
+	sort.Strings(keys)
+	for _, k := range keys {
+		if ps, ok := o.properties[k]; ok {
+			errs = append(errs, ps.validate(fmt.Sprintf("%s.%s", path, k), m[k])...)
+		}
+	}
+	return errs
+}
+
+func typeMismatch(path, want string, v interface{}) string {
+	return fmt.Sprintf("%s: expected %s; got %s (%v)", path, want, jsonTypeName(v), v)
+}
+
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// normalizeJSON converts an arbitrary go value into the generic form produced
+// by json.Unmarshal (so structs, typed slices, ints, etc. can all be validated
+// in the same way).
+func normalizeJSON(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var r interface{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ab, aErr := json.Marshal(a)
+	bb, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(ab) == string(bb)
+}
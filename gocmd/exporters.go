@@ -0,0 +1,494 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// commandEquivalent is the equivalent of a VS Code command in other editors.
+// An empty value means there is no equivalent in that editor.
+type commandEquivalent struct {
+	// jetbrains is the JetBrains action ID.
+	jetbrains string
+	// neovim is the right-hand side of a normal mode mapping.
+	neovim string
+	// emacs is the interactive command.
+	emacs string
+}
+
+var (
+	// commandEquivalents maps VS Code commands to their equivalents in other
+	// editors.
+	commandEquivalents = map[string]*commandEquivalent{
+		// Cursor movement
+		"groog.cursorUp":        {"EditorUp", "k", "previous-line"},
+		"groog.cursorDown":      {"EditorDown", "j", "next-line"},
+		"groog.cursorLeft":      {"EditorLeft", "h", "backward-char"},
+		"groog.cursorRight":     {"EditorRight", "l", "forward-char"},
+		"groog.cursorHome":      {"EditorLineStart", "0", "move-beginning-of-line"},
+		"groog.cursorEnd":       {"EditorLineEnd", "$", "move-end-of-line"},
+		"groog.cursorTop":       {"EditorTextStart", "gg", "beginning-of-buffer"},
+		"groog.cursorBottom":    {"EditorTextEnd", "G", "end-of-buffer"},
+		"groog.cursorWordLeft":  {"EditorPreviousWord", "b", "backward-word"},
+		"groog.cursorWordRight": {"EditorNextWord", "w", "forward-word"},
+		"groog.toggleMarkMode":  {"EditorToggleStickySelection", "v", "set-mark-command"},
+		"groog.jump":            {"EditorScrollUp", "<C-u>", "scroll-down-command"},
+		"groog.fall":            {"EditorScrollDown", "<C-d>", "scroll-up-command"},
+
+		// Editing
+		"groog.deleteLeft":                   {"EditorBackSpace", "X", "delete-backward-char"},
+		"groog.deleteRight":                  {"EditorDelete", "x", "delete-char"},
+		"groog.deleteWordLeft":               {"EditorDeleteToWordStart", "db", "backward-kill-word"},
+		"groog.deleteWordRight":              {"EditorDeleteToWordEnd", "dw", "kill-word"},
+		"groog.kill":                         {"EditorCutLineEnd", "D", "kill-line"},
+		"groog.paste":                        {"$Paste", "p", "yank"},
+		"groog.yank":                         {"$Paste", "p", "yank"},
+		"groog.undo":                         {"$Undo", "u", "undo"},
+		"groog.redo":                         {"$Redo", "<C-r>", "undo-redo"},
+		"groog.format":                       {"ReformatCode", "<cmd>lua vim.lsp.buf.format()<CR>", ""},
+		"editor.action.commentLine":          {"CommentByLineComment", "", "comment-line"},
+		"editor.action.indentLines":          {"EditorIndentSelection", ">>", "indent-rigidly-right-to-tab-stop"},
+		"editor.action.outdentLines":         {"EditorUnindentSelection", "<<", "indent-rigidly-left-to-tab-stop"},
+		"editor.action.selectAll":            {"$SelectAll", "ggVG", "mark-whole-buffer"},
+		"editor.action.organizeImports":      {"OptimizeImports", "", ""},
+		"editor.action.revealDefinition":     {"GotoDeclaration", "<cmd>lua vim.lsp.buf.definition()<CR>", "xref-find-definitions"},
+		"editor.action.clipboardPasteAction": {"$Paste", "p", "yank"},
+
+		// Find
+		"groog.find":                      {"Find", "/", "isearch-forward"},
+		"groog.reverseFind":               {"Find", "?", "isearch-backward"},
+		"groog.find.next":                 {"FindNext", "n", ""},
+		"groog.find.previous":             {"FindPrevious", "N", ""},
+		"workbench.action.findInFiles":    {"FindInPath", "", "rgrep"},
+		"workbench.action.replaceInFiles": {"ReplaceInPath", "", "project-query-replace-regexp"},
+
+		// Recording
+		"groog.record.startRecording": {"StartStopMacroRecording", "qq", "kmacro-start-macro"},
+		"groog.record.endRecording":   {"StartStopMacroRecording", "q", "kmacro-end-macro"},
+		"groog.record.playRecording":  {"PlaybackLastMacro", "@q", "kmacro-end-and-call-macro"},
+
+		// Files and windows
+		"workbench.action.files.save":                  {"SaveAll", "<cmd>write<CR>", "save-buffer"},
+		"workbench.action.files.saveWithoutFormatting": {"SaveAll", "<cmd>noautocmd write<CR>", "save-buffer"},
+		"workbench.action.gotoLine":                    {"GotoLine", "", "goto-line"},
+		"workbench.action.quickOpen":                   {"GotoFile", "", "find-file"},
+		"workbench.action.showCommands":                {"GotoAction", "", "execute-extended-command"},
+		"workbench.action.openRecent":                  {"RecentFiles", "", "recentf-open-files"},
+		"workbench.action.openSettings":                {"ShowSettings", "", "customize"},
+		"groog.focusNextEditor":                        {"NextTab", "<cmd>bnext<CR>", "next-buffer"},
+		"groog.focusPreviousEditor":                    {"PreviousTab", "<cmd>bprevious<CR>", "previous-buffer"},
+		"workbench.action.closeEditorsAndGroup":        {"CloseContent", "<cmd>close<CR>", "delete-window"},
+		"workbench.action.closeWindow":                 {"Exit", "<cmd>qall<CR>", "save-buffers-kill-terminal"},
+		"workbench.action.terminal.focus":              {"ActivateTerminalToolWindow", "<cmd>terminal<CR>", "shell"},
+		"workbench.action.toggleSidebarVisibility":     {"ActivateProjectToolWindow", "", ""},
+		"workbench.action.editor.nextChange":           {"VcsShowNextChangeMarker", "", ""},
+		"workbench.action.editor.previousChange":       {"VcsShowPrevChangeMarker", "", ""},
+		"gitlens.toggleLineBlame":                      {"Annotate", "", "vc-annotate"},
+	}
+
+	keybindingExporters = map[string]keybindingExporter{
+		"jetbrains": &jetbrainsExporter{},
+		"neovim":    &neovimExporter{},
+		"emacs":     &emacsExporter{},
+	}
+)
+
+// keybindingExporter converts keybindings into another editor's format.
+type keybindingExporter interface {
+	// filename is the default output file.
+	filename() string
+	// target returns the editor's equivalent of the command.
+	target(ce *commandEquivalent) string
+	// key converts a VS Code key (a single key press, e.g. `ctrl+shift+a`).
+	key(modifiers []string, key string) (string, error)
+	// chord joins the converted key presses of a chord.
+	chord(keys []string) (string, error)
+	// render returns the contents of the exported file.
+	render(bindings []*exportedBinding) string
+}
+
+// exportedBinding is a keybinding in another editor's format.
+type exportedBinding struct {
+	key     string
+	target  string
+	command string
+}
+
+// exportReport describes the keybindings that couldn't be exported.
+type exportReport struct {
+	// unmapped maps commands without an equivalent to the keys they're bound to.
+	unmapped map[string][]string
+	// unsupportedKeys maps keys that can't be represented to their commands.
+	unsupportedKeys map[string][]string
+	// conflicts are bindings that were skipped because the key was already
+	// exported (for a binding with a different when clause).
+	conflicts []string
+}
+
+func (er *exportReport) String() string {
+	var sb strings.Builder
+	if len(er.unmapped) > 0 {
+		fmt.Fprintf(&sb, "%d command(s) have no equivalent:\n", len(er.unmapped))
+		for _, cmd := range sortedKeys(er.unmapped) {
+			fmt.Fprintf(&sb, "  %s (%s)\n", cmd, uniqueKeys(er.unmapped[cmd]))
+		}
+	}
+	if len(er.unsupportedKeys) > 0 {
+		fmt.Fprintf(&sb, "%d key(s) can't be represented:\n", len(er.unsupportedKeys))
+		for _, k := range sortedKeys(er.unsupportedKeys) {
+			fmt.Fprintf(&sb, "  `%s` (%s)\n", k, strings.Join(er.unsupportedKeys[k], ", "))
+		}
+	}
+	if len(er.conflicts) > 0 {
+		fmt.Fprintf(&sb, "%d keybinding(s) were skipped because the key was already exported:\n", len(er.conflicts))
+		for _, c := range er.conflicts {
+			fmt.Fprintf(&sb, "  %s\n", c)
+		}
+	}
+	return sb.String()
+}
+
+// findKeybindingExporter returns the exporter for the format.
+func findKeybindingExporter(format string) (keybindingExporter, error) {
+	e, ok := keybindingExporters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q (expected one of %v)", format, sortedKeys(keybindingExporters))
+	}
+	return e, nil
+}
+
+// exportKeybindings converts the keybindings with the exporter. Other editors
+// don't support when clauses, so only the first exportable binding for each
+// key is included.
+func exportKeybindings(e keybindingExporter, kbs []*Keybinding) (string, *exportReport) {
+	report := &exportReport{
+		unmapped:        map[string][]string{},
+		unsupportedKeys: map[string][]string{},
+	}
+
+	var bindings []*exportedBinding
+	exported := map[string]*exportedBinding{}
+	for _, kb := range kbs {
+		if strings.HasPrefix(kb.Command, "-") || kb.Command == "groog.type" {
+			continue
+		}
+
+		var target string
+		if ce, ok := commandEquivalents[kb.Command]; ok {
+			target = e.target(ce)
+		}
+		if target == "" {
+			report.unmapped[kb.Command] = append(report.unmapped[kb.Command], kb.Key)
+			continue
+		}
+
+		key, err := exportKey(e, kb.Key)
+		if err != nil {
+			report.unsupportedKeys[kb.Key] = append(report.unsupportedKeys[kb.Key], kb.Command)
+			continue
+		}
+
+		if prev, ok := exported[key]; ok {
+			if prev.target != target {
+				report.conflicts = append(report.conflicts, fmt.Sprintf("`%s` → %s (already bound to %s)", kb.Key, kb.Command, prev.command))
+			}
+			continue
+		}
+
+		eb := &exportedBinding{key, target, kb.Command}
+		exported[key] = eb
+		bindings = append(bindings, eb)
+	}
+	return e.render(bindings), report
+}
+
+// exportKey converts a VS Code key (or chord) with the exporter.
+func exportKey(e keybindingExporter, vsKey string) (string, error) {
+	var keys []string
+	for _, press := range strings.Fields(vsKey) {
+		parts := strings.Split(press, "+")
+		k, err := e.key(parts[:len(parts)-1], parts[len(parts)-1])
+		if err != nil {
+			return "", err
+		}
+		keys = append(keys, k)
+	}
+	return e.chord(keys)
+}
+
+// splitModifiers returns whether each modifier is set (and an error for
+// unknown modifiers).
+func splitModifiers(modifiers []string) (ctrl, alt, shift bool, err error) {
+	for _, m := range modifiers {
+		switch m {
+		case "ctrl":
+			ctrl = true
+		case "alt":
+			alt = true
+		case "shift":
+			shift = true
+		default:
+			return false, false, false, fmt.Errorf("unsupported modifier %q", m)
+		}
+	}
+	return ctrl, alt, shift, nil
+}
+
+/************
+ * JetBrains *
+ ************/
+
+var (
+	jetbrainsKeys = map[string]string{
+		up: "UP", down: "DOWN", left: "LEFT", right: "RIGHT",
+		pageup: "PAGE_UP", pagedown: "PAGE_DOWN",
+		backspace: "BACK_SPACE", delete: "DELETE",
+		home: "HOME", end: "END", insert: "INSERT",
+		tab: "TAB", enter: "ENTER", space: "SPACE", escape: "ESCAPE",
+		"`": "BACK_QUOTE", "-": "MINUS", "=": "EQUALS",
+		"[": "OPEN_BRACKET", "]": "CLOSE_BRACKET", `\`: "BACK_SLASH",
+		";": "SEMICOLON", "'": "QUOTE", ",": "COMMA", ".": "PERIOD", "/": "SLASH",
+	}
+)
+
+type jetbrainsExporter struct{}
+
+func (*jetbrainsExporter) filename() string {
+	return "groog.xml"
+}
+
+func (*jetbrainsExporter) target(ce *commandEquivalent) string {
+	return ce.jetbrains
+}
+
+func (*jetbrainsExporter) key(modifiers []string, key string) (string, error) {
+	ctrl, alt, shift, err := splitModifiers(modifiers)
+	if err != nil {
+		return "", err
+	}
+
+	var parts []string
+	if ctrl {
+		parts = append(parts, "control")
+	}
+	if alt {
+		parts = append(parts, "alt")
+	}
+	if shift {
+		parts = append(parts, "shift")
+	}
+
+	k, ok := jetbrainsKeys[key]
+	switch {
+	case ok:
+	case len(key) == 1 && (key[0] >= 'a' && key[0] <= 'z' || key[0] >= '0' && key[0] <= '9'):
+		k = strings.ToUpper(key)
+	case functionKey(key):
+		k = strings.ToUpper(key)
+	default:
+		return "", fmt.Errorf("unsupported key %q", key)
+	}
+	return strings.Join(append(parts, k), " "), nil
+}
+
+func (*jetbrainsExporter) chord(keys []string) (string, error) {
+	// JetBrains shortcuts have at most two keystrokes.
+	if len(keys) > 2 {
+		return "", fmt.Errorf("chords with more than two keys are not supported")
+	}
+	return strings.Join(keys, "\n"), nil
+}
+
+func (*jetbrainsExporter) render(bindings []*exportedBinding) string {
+	byAction := map[string][]string{}
+	for _, b := range bindings {
+		byAction[b.target] = append(byAction[b.target], b.key)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	sb.WriteString("<keymap version=\"1\" name=\"groog\" parent=\"$default\">\n")
+	for _, action := range sortedKeys(byAction) {
+		fmt.Fprintf(&sb, "  <action id=\"%s\">\n", xmlEscape(action))
+		keys := byAction[action]
+		sort.Strings(keys)
+		for _, k := range keys {
+			first, second, _ := strings.Cut(k, "\n")
+			fmt.Fprintf(&sb, "    <keyboard-shortcut first-keystroke=\"%s\"", xmlEscape(first))
+			if second != "" {
+				fmt.Fprintf(&sb, " second-keystroke=\"%s\"", xmlEscape(second))
+			}
+			sb.WriteString(" />\n")
+		}
+		sb.WriteString("  </action>\n")
+	}
+	sb.WriteString("</keymap>\n")
+	return sb.String()
+}
+
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+/**********
+ * Neovim *
+ **********/
+
+var (
+	neovimKeys = map[string]string{
+		up: "Up", down: "Down", left: "Left", right: "Right",
+		pageup: "PageUp", pagedown: "PageDown",
+		backspace: "BS", delete: "Del",
+		home: "Home", end: "End", insert: "Insert",
+		tab: "Tab", enter: "CR", space: "Space", escape: "Esc",
+		`\`: "Bslash", "<": "lt",
+	}
+)
+
+type neovimExporter struct{}
+
+func (*neovimExporter) filename() string {
+	return "groog.lua"
+}
+
+func (*neovimExporter) target(ce *commandEquivalent) string {
+	return ce.neovim
+}
+
+func (*neovimExporter) key(modifiers []string, key string) (string, error) {
+	ctrl, alt, shift, err := splitModifiers(modifiers)
+	if err != nil {
+		return "", err
+	}
+
+	k, special := neovimKeys[key]
+	switch {
+	case special:
+	case functionKey(key):
+		k, special = strings.ToUpper(key), true
+	case len(key) == 1:
+		k = key
+		// Shifted letters are typed as uppercase letters (except with ctrl, which
+		// ignores case).
+		if shift && !ctrl && key[0] >= 'a' && key[0] <= 'z' {
+			k, shift = strings.ToUpper(key), false
+		}
+	default:
+		return "", fmt.Errorf("unsupported key %q", key)
+	}
+
+	var prefix string
+	if ctrl {
+		prefix += "C-"
+	}
+	if alt {
+		prefix += "M-"
+	}
+	if shift {
+		prefix += "S-"
+	}
+	if prefix == "" && !special {
+		return k, nil
+	}
+	return fmt.Sprintf("<%s%s>", prefix, k), nil
+}
+
+func (*neovimExporter) chord(keys []string) (string, error) {
+	return strings.Join(keys, ""), nil
+}
+
+func (*neovimExporter) render(bindings []*exportedBinding) string {
+	var sb strings.Builder
+	sb.WriteString("-- Generated by `vs-package export neovim`. Do not edit.\n")
+	sb.WriteString("local map = vim.keymap.set\n\n")
+	for _, b := range bindings {
+		fmt.Fprintf(&sb, "map(\"n\", %s, %s, { desc = %s })\n", strconv.Quote(b.key), strconv.Quote(b.target), strconv.Quote(b.command))
+	}
+	return sb.String()
+}
+
+/*********
+ * Emacs *
+ *********/
+
+var (
+	emacsKeys = map[string]string{
+		up: "<up>", down: "<down>", left: "<left>", right: "<right>",
+		pageup: "<prior>", pagedown: "<next>",
+		backspace: "DEL", delete: "<delete>",
+		home: "<home>", end: "<end>", insert: "<insert>",
+		tab: "TAB", enter: "RET", space: "SPC", escape: "ESC",
+	}
+)
+
+type emacsExporter struct{}
+
+func (*emacsExporter) filename() string {
+	return "groog.el"
+}
+
+func (*emacsExporter) target(ce *commandEquivalent) string {
+	return ce.emacs
+}
+
+func (*emacsExporter) key(modifiers []string, key string) (string, error) {
+	ctrl, alt, shift, err := splitModifiers(modifiers)
+	if err != nil {
+		return "", err
+	}
+
+	k, ok := emacsKeys[key]
+	switch {
+	case ok:
+	case functionKey(key):
+		k = fmt.Sprintf("<%s>", key)
+	case len(key) == 1:
+		k = key
+		// Shifted letters are typed as uppercase letters (except with ctrl, which
+		// ignores case).
+		if shift && !ctrl && key[0] >= 'a' && key[0] <= 'z' {
+			k, shift = strings.ToUpper(key), false
+		}
+	default:
+		return "", fmt.Errorf("unsupported key %q", key)
+	}
+
+	var prefix string
+	if ctrl {
+		prefix += "C-"
+	}
+	if alt {
+		prefix += "M-"
+	}
+	if shift {
+		prefix += "S-"
+	}
+	return prefix + k, nil
+}
+
+func (*emacsExporter) chord(keys []string) (string, error) {
+	return strings.Join(keys, " "), nil
+}
+
+func (*emacsExporter) render(bindings []*exportedBinding) string {
+	var sb strings.Builder
+	sb.WriteString(";;; groog.el --- Generated by `vs-package export emacs`. Do not edit.  -*- lexical-binding: t -*-\n\n")
+	for _, b := range bindings {
+		fmt.Fprintf(&sb, "(define-key global-map (kbd %s) #'%s) ; %s\n", strconv.Quote(b.key), b.target, b.command)
+	}
+	return sb.String()
+}
+
+// functionKey returns whether the key is a function key (e.g. `f12`).
+func functionKey(key string) bool {
+	if len(key) < 2 || key[0] != 'f' {
+		return false
+	}
+	_, err := strconv.Atoi(key[1:])
+	return err == nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportKeybindings(t *testing.T) {
+	kbs := kbDefsToBindings(defaultKeyboardLayout)
+	for _, format := range sortedKeys(keybindingExporters) {
+		t.Run(format, func(t *testing.T) {
+			e, err := findKeybindingExporter(format)
+			if err != nil {
+				t.Fatalf("findKeybindingExporter(%q) returned error: %v", format, err)
+			}
+
+			contents, report := exportKeybindings(e, kbs)
+			checkGolden(t, fmt.Sprintf("export/%s.golden%s", format, filepath.Ext(e.filename())), contents)
+			checkGolden(t, fmt.Sprintf("export/%s_report.golden", format), report.String())
+		})
+	}
+}
+
+func TestExportKey(t *testing.T) {
+	for _, test := range []struct {
+		format  string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{format: "jetbrains", key: "ctrl+x s", want: "control X\nS"},
+		{format: "jetbrains", key: "ctrl+shift+/", want: "control shift SLASH"},
+		{format: "jetbrains", key: "alt+pageup", want: "alt PAGE_UP"},
+		{format: "jetbrains", key: "f12", want: "F12"},
+		{format: "jetbrains", key: "ctrl+x ctrl+z a", wantErr: true},
+		{format: "neovim", key: "ctrl+x s", want: "<C-x>s"},
+		{format: "neovim", key: "shift+a", want: "A"},
+		{format: "neovim", key: "ctrl+shift+a", want: "<C-S-a>"},
+		{format: "neovim", key: "alt+shift+a", want: "<M-A>"},
+		{format: "neovim", key: "alt+backspace", want: "<M-BS>"},
+		{format: "neovim", key: "shift+enter", want: "<S-CR>"},
+		{format: "neovim", key: "\\", want: "<Bslash>"},
+		{format: "emacs", key: "ctrl+x ctrl+s", want: "C-x C-s"},
+		{format: "emacs", key: "alt+shift+i", want: "M-I"},
+		{format: "emacs", key: "ctrl+shift+k", want: "C-S-k"},
+		{format: "emacs", key: "ctrl+pagedown", want: "C-<next>"},
+		{format: "emacs", key: "shift+up", want: "S-<up>"},
+		{format: "emacs", key: "meta+a", wantErr: true},
+	} {
+		t.Run(fmt.Sprintf("%s %s", test.format, test.key), func(t *testing.T) {
+			e, err := findKeybindingExporter(test.format)
+			if err != nil {
+				t.Fatalf("findKeybindingExporter(%q) returned error: %v", test.format, err)
+			}
+
+			got, err := exportKey(e, test.key)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("exportKey(%q) returned error %v; want error: %v", test.key, err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("exportKey(%q) returned %q; want %q", test.key, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFindKeybindingExporter(t *testing.T) {
+	if _, err := findKeybindingExporter("vim"); err == nil {
+		t.Errorf("findKeybindingExporter(\"vim\") returned nil error")
+	}
+}
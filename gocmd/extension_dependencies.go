@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// companionExtensions are the extensions (and the command namespaces they
+	// contribute) whose commands are bound in kbDefinitions.
+	companionExtensions = []*CompanionExtension{
+		{Namespace: "termin-all-or-nothing", ID: "groogle.termin-all-or-nothing"},
+		{Namespace: "faves", ID: "groogle.faves", Pack: true},
+		{Namespace: "very-import-ant", ID: "groogle.very-import-ant", Pack: true},
+		{Namespace: "coverage-gutters", ID: "ryanluker.vscode-coverage-gutters", Pack: true},
+		{Namespace: "groog-remote", ID: "groogle.groog-remote"},
+		{Namespace: "gitlens", ID: "eamodio.gitlens"},
+		{Namespace: "cSpell", ID: "streetsidesoftware.code-spell-checker"},
+		{Namespace: "jupyter", ID: "ms-toolsai.jupyter"},
+		{Namespace: "go", ID: "golang.go"},
+		{Namespace: "github.copilot", ID: "github.copilot"},
+		{Namespace: "remote-wsl", ID: "ms-vscode-remote.remote-wsl"},
+		// This extension contributes its commands under the generic `extension`
+		// namespace. Its `ctrl+l` keybindings are removed (see
+		// removeKeybindings), so it isn't installed by the profile.
+		{Namespace: "extension", ID: "ziyasal.vscode-open-in-github"},
+	}
+)
+
+// CompanionExtension is an extension whose commands are bound by groog.
+type CompanionExtension struct {
+	// Namespace is the command prefix (e.g. `gitlens` for
+	// `gitlens.toggleLineBlame`).
+	Namespace string
+	// ID is the extension identifier (`<publisher>.<name>`).
+	ID string
+	// Pack indicates whether the extension should be included in groog's
+	// extensionPack (so it is installed alongside groog). Unlike
+	// extensionDependencies, packed extensions can still be uninstalled.
+	Pack bool
+}
+
+// commandExtension returns the companion extension that contributes the
+// command (or nil if the command isn't from a companion extension).
+func commandExtension(cmd string) *CompanionExtension {
+	cmd = strings.TrimPrefix(cmd, "-")
+	var match *CompanionExtension
+	for _, ce := range companionExtensions {
+		if strings.HasPrefix(cmd, ce.Namespace+".") && (match == nil || len(ce.Namespace) > len(match.Namespace)) {
+			match = ce
+		}
+	}
+	return match
+}
+
+// extensionPack returns the IDs of the companion extensions that should be
+// installed alongside groog.
+func extensionPack() []string {
+	var ids []string
+	for _, ce := range companionExtensions {
+		if ce.Pack {
+			ids = append(ids, ce.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// keybindingCommands returns all of the commands run by the keybinding
+// (including the commands nested in multi-command sequences).
+func keybindingCommands(kb *Keybinding) []string {
+	cmds := []string{kb.Command}
+	return append(cmds, nestedCommands(kb.Args)...)
+}
+
+func nestedCommands(v interface{}) []string {
+	var cmds []string
+	switch t := v.(type) {
+	case *KB:
+		cmds = append(cmds, t.Command)
+		cmds = append(cmds, nestedCommands(t.Args)...)
+	case []*KB:
+		for _, kb := range t {
+			cmds = append(cmds, nestedCommands(kb)...)
+		}
+	case map[string]interface{}:
+		if cmd, ok := t["command"].(string); ok {
+			cmds = append(cmds, cmd)
+		}
+		for _, k := range sortedKeys(t) {
+			cmds = append(cmds, nestedCommands(t[k])...)
+		}
+	case []map[string]interface{}:
+		for _, m := range t {
+			cmds = append(cmds, nestedCommands(m)...)
+		}
+	case []interface{}:
+		for _, e := range t {
+			cmds = append(cmds, nestedCommands(e)...)
+		}
+	}
+	return cmds
+}
+
+// extensionBinding is a keybinding that runs a companion extension command.
+type extensionBinding struct {
+	binding *Keybinding
+	command string
+}
+
+func (eb *extensionBinding) String() string {
+	if eb.command == eb.binding.Command {
+		return keybindingString(eb.binding)
+	}
+	return fmt.Sprintf("%s (via %s)", keybindingString(eb.binding), eb.command)
+}
+
+// extensionBindings returns the keybindings that depend on each companion
+// extension (by extension ID).
+func extensionBindings(kbs []*Keybinding) map[string][]*extensionBinding {
+	m := map[string][]*extensionBinding{}
+	for _, kb := range kbs {
+		for _, cmd := range keybindingCommands(kb) {
+			// Removing a missing extension's keybinding doesn't break anything.
+			if strings.HasPrefix(cmd, "-") {
+				continue
+			}
+			if ce := commandExtension(cmd); ce != nil {
+				m[ce.ID] = append(m[ce.ID], &extensionBinding{kb, cmd})
+			}
+		}
+	}
+	return m
+}
+
+// extensionReport returns a report of the keybindings that break when each of
+// the provided extensions is missing (or all companion extensions if none are
+// provided).
+func extensionReport(kbs []*Keybinding, missing []string) (string, error) {
+	if len(missing) == 0 {
+		for _, ce := range companionExtensions {
+			missing = append(missing, ce.ID)
+		}
+		sort.Strings(missing)
+	}
+
+	known := map[string]bool{}
+	for _, ce := range companionExtensions {
+		known[ce.ID] = true
+	}
+
+	byExtension := extensionBindings(kbs)
+	var sb strings.Builder
+	for _, id := range missing {
+		if !known[id] {
+			return "", fmt.Errorf("unknown companion extension %q", id)
+		}
+
+		ebs := byExtension[id]
+		fmt.Fprintf(&sb, "%s (%d keybinding(s)):\n", id, len(ebs))
+		for _, eb := range ebs {
+			fmt.Fprintf(&sb, "  %s\n", eb)
+		}
+	}
+	return sb.String(), nil
+}
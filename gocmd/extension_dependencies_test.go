@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNestedCommands(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		v    interface{}
+		want []string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "args without commands",
+			v:    map[string]interface{}{"text": "abc"},
+		},
+		{
+			name: "KB",
+			v: &KB{
+				Command: "termin-all-or-nothing.execute",
+				Args:    map[string]interface{}{"command": "faves.search"},
+			},
+			want: []string{"termin-all-or-nothing.execute", "faves.search"},
+		},
+		{
+			name: "KB list",
+			v: []*KB{
+				{Command: "groog.cursorUp"},
+				{Command: "gitlens.toggleLineBlame"},
+			},
+			want: []string{"groog.cursorUp", "gitlens.toggleLineBlame"},
+		},
+		{
+			name: "multi-command sequence",
+			v: map[string]interface{}{
+				"sequence": []map[string]interface{}{
+					{"command": "cSpell.addWordToUserDictionary"},
+					{"command": "groog.message.info", "args": map[string]interface{}{"message": "added"}},
+				},
+			},
+			want: []string{"cSpell.addWordToUserDictionary", "groog.message.info"},
+		},
+		{
+			name: "unmarshalled json",
+			v: map[string]interface{}{
+				"sequence": []interface{}{
+					map[string]interface{}{"command": "jupyter.runcurrentcell"},
+					map[string]interface{}{
+						"command": "termin-all-or-nothing.execute",
+						"args":    map[string]interface{}{"command": "go.test.package"},
+					},
+				},
+			},
+			want: []string{"jupyter.runcurrentcell", "termin-all-or-nothing.execute", "go.test.package"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := nestedCommands(test.v)
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("nestedCommands() returned %v; want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCommandExtension(t *testing.T) {
+	for _, test := range []struct {
+		cmd  string
+		want string
+	}{
+		{"gitlens.toggleLineBlame", "eamodio.gitlens"},
+		{"-gitlens.toggleLineBlame", "eamodio.gitlens"},
+		{"github.copilot.generate", "github.copilot"},
+		{"groog.cursorUp", ""},
+		{"gitlensy.command", ""},
+	} {
+		t.Run(test.cmd, func(t *testing.T) {
+			var got string
+			if ce := commandExtension(test.cmd); ce != nil {
+				got = ce.ID
+			}
+			if got != test.want {
+				t.Errorf("commandExtension(%q) returned %q; want %q", test.cmd, got, test.want)
+			}
+		})
+	}
+}
+
+func TestExtensionReport(t *testing.T) {
+	kbs := []*Keybinding{
+		{Key: "ctrl+b", Command: "gitlens.toggleLineBlame"},
+		{Key: "ctrl+f", Command: "faves.search", When: "editorTextFocus"},
+		{
+			Key:     "ctrl+t",
+			Command: "groog.multiCommand.execute",
+			Args: map[string]interface{}{
+				"sequence": []map[string]interface{}{
+					{"command": "termin-all-or-nothing.closePanel"},
+					{"command": "faves.search"},
+				},
+			},
+		},
+		{Key: "ctrl+l", Command: "-gitlens.showQuickCommitFileDetails"},
+		{Key: "ctrl+u", Command: "groog.undo"},
+	}
+
+	for _, test := range []struct {
+		name    string
+		missing []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "single extension",
+			missing: []string{"eamodio.gitlens"},
+			want: []string{
+				"eamodio.gitlens (1 keybinding(s)):",
+				"  `ctrl+b` → `gitlens.toggleLineBlame`",
+			},
+		},
+		{
+			name:    "nested commands",
+			missing: []string{"groogle.faves", "groogle.termin-all-or-nothing"},
+			want: []string{
+				"groogle.faves (2 keybinding(s)):",
+				"  `ctrl+f` → `faves.search` when `editorTextFocus`",
+				"  `ctrl+t` → `groog.multiCommand.execute` with args `{\"sequence\":[{\"command\":\"termin-all-or-nothing.closePanel\"},{\"command\":\"faves.search\"}]}` (via faves.search)",
+				"groogle.termin-all-or-nothing (1 keybinding(s)):",
+				"  `ctrl+t` → `groog.multiCommand.execute` with args `{\"sequence\":[{\"command\":\"termin-all-or-nothing.closePanel\"},{\"command\":\"faves.search\"}]}` (via termin-all-or-nothing.closePanel)",
+			},
+		},
+		{
+			name:    "unused extension",
+			missing: []string{"golang.go"},
+			want: []string{
+				"golang.go (0 keybinding(s)):",
+			},
+		},
+		{
+			name:    "unknown extension",
+			missing: []string{"some.extension"},
+			wantErr: `unknown companion extension "some.extension"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := extensionReport(kbs, test.missing)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("extensionReport() returned error %v; want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extensionReport() returned error: %v", err)
+			}
+			if want := strings.Join(test.want, "\n") + "\n"; got != want {
+				t.Errorf("extensionReport() returned:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	t.Run("all extensions", func(t *testing.T) {
+		got, err := extensionReport(kbs, nil)
+		if err != nil {
+			t.Fatalf("extensionReport() returned error: %v", err)
+		}
+		for _, ce := range companionExtensions {
+			if !strings.Contains(got, fmt.Sprintf("\n%s (", ce.ID)) && !strings.HasPrefix(got, ce.ID+" (") {
+				t.Errorf("extensionReport() does not include companion extension %q:\n%s", ce.ID, got)
+			}
+		}
+	})
+}
@@ -71,6 +71,12 @@ type OperationContext struct {
 func (oc *OperationContext) value() string {
 	var values []string
 	for _, part := range oc.parts {
+		// `&&` has higher precedence than `||`, so nested operations need to be
+		// parenthesized (unless they're the same operation).
+		if nested, ok := part.(*OperationContext); ok && nested.operation != oc.operation && len(nested.parts) > 1 {
+			values = append(values, fmt.Sprintf("(%s)", nested.value()))
+			continue
+		}
 		values = append(values, part.value())
 	}
 	return fmt.Sprintf("%s", strings.Join(values, fmt.Sprintf(" %s ", oc.operation)))
@@ -155,22 +161,6 @@ var (
 	javaFile       = whenFileType("java")
 	typescriptFile = whenFileType("typescript")
 
-	// Ignore typing when in find widget
-	characters = strings.Join([]string{
-		// Keyboard rows
-		"`1234567890-=",
-		`qwertyuiop[]\`,
-		`asdfghjkl;'`,
-		`zxcvbnm,./`,
-	}, "")
-	shiftedCharacters = strings.Join([]string{
-		// Keyboard rows
-		`~!@#$%^&*()_+`,
-		`QWERTYUIOP{}|`,
-		`ASDFGHJKL:"`,
-		`ZXCVBNM<>?`,
-	}, "")
-
 	// The context to use for keys that should have no binding in global find or
 	// input boxes, etc.
 	groogBehaviorContext = and(or(editorTextFocus, findInputFocussed, inQuickOpen), groogFindMode, debugConsoleFocus.not())
@@ -198,29 +188,29 @@ const (
 	escape    = "escape"
 )
 
-func kbDefsToBindings() []*Keybinding {
-	// First add overrides when not in text editor
-	for ci, c := range characters {
-		k := Key(c)
-		for si, s := range []Key{k, shift(k)} {
-			if _, ok := kbDefinitions[s]; ok {
-				panic(fmt.Sprintf("kbDefinitions already contains key for %s", s))
-			}
-			text := s
-			if si != 0 {
-				text = Key(shiftedCharacters[ci])
-			}
+// kbDefsToBindings converts kbDefinitions (along with the groog.type
+// overrides for the provided keyboard layout) into keybindings.
+func kbDefsToBindings(layout *KeyboardLayout) []*Keybinding {
+	defs := map[Key]map[string]*KB{}
+	for k, m := range kbDefinitions {
+		defs[k] = m
+	}
 
-			kbDefinitions[s] = map[string]*KB{
-				groogBehaviorContext.value(): kbArgs("groog.type", map[string]interface{}{
-					"text": text,
-				}),
-			}
+	// First add overrides when not in text editor
+	// (ignore typing when in find widget)
+	if conflicts := layout.overrideConflicts(defs); len(conflicts) > 0 {
+		panic(fmt.Sprintf("kbDefinitions already contains keys for layout keys:\n%s", strings.Join(conflicts, "\n")))
+	}
+	for k, text := range layout.typeOverrides() {
+		defs[k] = map[string]*KB{
+			groogBehaviorContext.value(): kbArgs("groog.type", map[string]interface{}{
+				"text": text,
+			}),
 		}
 	}
 
 	// Then create all json values
-	keys := append(maps.Keys(kbDefinitions), maps.Keys(removeKeybindings)...)
+	keys := append(maps.Keys(defs), maps.Keys(removeKeybindings)...)
 	slices.Sort(keys)
 
 	var kbs []*Keybinding
@@ -233,7 +223,7 @@ func kbDefsToBindings() []*Keybinding {
 		visited[key] = true
 
 		// Add the new keybindings
-		m := kbDefinitions[key]
+		m := defs[key]
 		whens := maps.Keys(m)
 		slices.Sort(whens)
 
@@ -743,7 +733,8 @@ var (
 				},
 				&KB{
 					Command: "groog.testFile",
-					Delay:   delay(25),
+					// Delay is determined by the groog.testFile.delay setting
+					DelaySetting: "testFile.delay",
 					Args: map[string]interface{}{
 						"part": 1,
 					},
@@ -781,6 +772,8 @@ type KB struct {
 	// This is for multi-command (it's a pointer so omitempty works)
 	Async *bool `json:"async,omitempty"`
 	Delay *int  `json:"delay,omitempty"`
+	// The groog setting containing the delay (takes precedence over Delay).
+	DelaySetting string `json:"delaySetting,omitempty"`
 }
 
 func terminAllOrNothingWrap(command string, args map[string]interface{}) map[string]interface{} {
@@ -1002,21 +995,15 @@ func popLeader(k Key) (string, bool) {
 	return fmt.Sprintf("%s ctrl+%s", m[1], m[2]), true
 }
 
-func repeat(c string, times int) []string {
-	var r []string
-	for ; times > 0; times-- {
-		r = append(r, c)
-	}
-	return r
-}
-
 /***************************************
  * key functions for multiple bindings *
  ***************************************/
 
 func ctrlLBindings() map[string]*KB {
 	return map[string]*KB{
-		inQuickOpen.value(): mc(repeat("workbench.action.quickOpenNavigatePreviousInFilePicker", 5)...),
+		inQuickOpen.value(): kbArgs("groog.quickOpen.page", map[string]interface{}{
+			"previous": true,
+		}),
 		and(inQuickOpen.not(), terminalFocus.not()).value(): kb("groog.jump"),
 		// Sending this sequence sends the equivalent of pressing the page-up key while in the terminal.
 		// See this stack overflow post: https://stackoverflow.com/questions/61742559/need-vscode-sendsequence-keybindings-for-previous-command-next-command-move-to
@@ -1027,21 +1014,18 @@ func ctrlLBindings() map[string]*KB {
 
 func ctrlVBindings() map[string]*KB {
 	return map[string]*KB{
-		inQuickOpen.value(): mc(repeat("workbench.action.quickOpenNavigateNextInFilePicker", 5)...),
+		inQuickOpen.value(): kb("groog.quickOpen.page"),
 		and(inQuickOpen.not(), terminalFocus.not()).value(): kb("groog.fall"),
 		// See ctrlLBindings function for description of what this means
 		and(inQuickOpen.not(), terminalFocus).value(): sendSequence("\u001b[6~"),
 	}
 }
 
-const (
-	superJump = 50
-)
-
 func ctrlShiftLBindings() map[string]*KB {
 	return map[string]*KB{
+		// Number of lines is determined by the groog.jump.superJumpLines setting
 		always.value(): kbArgs("groog.jump", map[string]interface{}{
-			"lines": superJump,
+			"superJump": true,
 		}),
 	}
 }
@@ -1049,7 +1033,7 @@ func ctrlShiftLBindings() map[string]*KB {
 func ctrlShiftVBindings() map[string]*KB {
 	return map[string]*KB{
 		always.value(): kbArgs("groog.fall", map[string]interface{}{
-			"lines": superJump,
+			"superJump": true,
 		}),
 	}
 }
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWhenContextValue(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		context WhenContext
+		want    string
+	}{
+		{
+			name:    "simple context",
+			context: wc("editorTextFocus"),
+			want:    "editorTextFocus",
+		},
+		{
+			name:    "negated context",
+			context: wc("editorTextFocus").not(),
+			want:    "!editorTextFocus",
+		},
+		{
+			name:    "double negated context",
+			context: wc("editorTextFocus").not().not(),
+			want:    "editorTextFocus",
+		},
+		{
+			name:    "groog context",
+			context: groogFindMode,
+			want:    "groog.context.findMode",
+		},
+		{
+			name:    "file type comparison",
+			context: goFile,
+			want:    "resourceLangId == go",
+		},
+		{
+			name:    "negated file type comparison",
+			context: notGoFile,
+			want:    "resourceLangId != go",
+		},
+		{
+			name:    "and",
+			context: and(editorTextFocus, terminalFocus.not()),
+			want:    "editorTextFocus && !terminalFocus",
+		},
+		{
+			name:    "or",
+			context: or(editorTextFocus, inQuickOpen),
+			want:    "editorTextFocus || inQuickOpen",
+		},
+		{
+			name:    "nested operations",
+			context: and(or(editorTextFocus, inQuickOpen), groogFindMode),
+			want:    "(editorTextFocus || inQuickOpen) && groog.context.findMode",
+		},
+		{
+			name:    "nested and operation",
+			context: or(and(editorTextFocus, inQuickOpen), groogFindMode),
+			want:    "(editorTextFocus && inQuickOpen) || groog.context.findMode",
+		},
+		{
+			name:    "nested same operation",
+			context: and(and(editorTextFocus, inQuickOpen), groogFindMode),
+			want:    "editorTextFocus && inQuickOpen && groog.context.findMode",
+		},
+		{
+			name:    "nested single context",
+			context: and(or(editorTextFocus), groogFindMode),
+			want:    "editorTextFocus && groog.context.findMode",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.context.value(); got != test.want {
+				t.Errorf("value() returned %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPopLeader(t *testing.T) {
+	for _, test := range []struct {
+		key    Key
+		want   string
+		wantOK bool
+	}{
+		{
+			key:    ctrlX("s"),
+			want:   "ctrl+x ctrl+s",
+			wantOK: true,
+		},
+		{
+			key:    ctrlZ(left),
+			want:   "ctrl+z ctrl+left",
+			wantOK: true,
+		},
+		{
+			key:    ctrlLeader("l", shift("g").ToString()),
+			want:   "ctrl+l ctrl+shift+g",
+			wantOK: true,
+		},
+		{
+			key: ctrl("s"),
+		},
+		{
+			key: "s",
+		},
+		{
+			key: alt("x") + " s",
+		},
+	} {
+		t.Run(string(test.key), func(t *testing.T) {
+			got, ok := popLeader(test.key)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("popLeader(%q) returned (%q, %v); want (%q, %v)", test.key, got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestKeyAliases(t *testing.T) {
+	for _, test := range []struct {
+		key  Key
+		want []string
+	}{
+		{
+			key:  "a",
+			want: []string{"a"},
+		},
+		{
+			key:  ctrl(alt(shift("a"))),
+			want: []string{"ctrl+alt+shift+a"},
+		},
+		{
+			key:  ctrlX("f"),
+			want: []string{"ctrl+x f", "ctrl+x ctrl+f"},
+		},
+		{
+			key:  ctrlZ(down),
+			want: []string{"ctrl+z down", "ctrl+z ctrl+down"},
+		},
+	} {
+		t.Run(string(test.key), func(t *testing.T) {
+			got := test.key.keyAliases()
+			if strings.Join(got, "|") != strings.Join(test.want, "|") {
+				t.Errorf("keyAliases(%q) returned %q; want %q", test.key, got, test.want)
+			}
+		})
+	}
+}
+
+func TestKbDefsToBindings(t *testing.T) {
+	// kbDefsToBindings shouldn't modify kbDefinitions, so repeated calls should
+	// return the same keybindings (see TestGroogPackage for the golden file).
+	first, err := marshalJson(kbDefsToBindings(defaultKeyboardLayout))
+	if err != nil {
+		t.Fatalf("marshalJson() returned error: %v", err)
+	}
+	second, err := marshalJson(kbDefsToBindings(defaultKeyboardLayout))
+	if err != nil {
+		t.Fatalf("marshalJson() returned error: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("kbDefsToBindings() returned different keybindings when called twice")
+	}
+}
+
+func TestKeyboardLayoutTypeOverrides(t *testing.T) {
+	for _, kl := range keyboardLayouts {
+		t.Run(kl.Name, func(t *testing.T) {
+			overrides := map[string]string{}
+			for k, text := range kl.typeOverrides() {
+				overrides[k.ToString()] = text
+			}
+
+			var sb strings.Builder
+			for _, k := range sortedKeys(overrides) {
+				fmt.Fprintf(&sb, "%s\t%s\n", k, overrides[k])
+			}
+			checkGolden(t, fmt.Sprintf("keyboard_layouts/%s.golden", kl.Name), sb.String())
+		})
+	}
+}
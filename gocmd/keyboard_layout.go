@@ -0,0 +1,379 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// See the following link for details on how VS Code dispatches keybindings
+// for non-US keyboard layouts:
+// https://github.com/microsoft/vscode/wiki/Keybinding-Issues
+
+const (
+	// keyboardLayoutEnv is the environment variable used to select the
+	// keyboard layout when the `--layout` flag isn't provided.
+	keyboardLayoutEnv = "GROOG_KEYBOARD_LAYOUT"
+
+	// noText is used in layout rows for keys that don't type a character on
+	// their own (e.g. dead keys). No groog.type override is generated for them.
+	noText = ' '
+)
+
+var (
+	// ansiScanCodes are the VS Code scan codes for the character keys of an ANSI
+	// keyboard, in the same order as the layout rows.
+	ansiScanCodes = [][]string{
+		{"Backquote", "Digit1", "Digit2", "Digit3", "Digit4", "Digit5", "Digit6", "Digit7", "Digit8", "Digit9", "Digit0", "Minus", "Equal"},
+		{"KeyQ", "KeyW", "KeyE", "KeyR", "KeyT", "KeyY", "KeyU", "KeyI", "KeyO", "KeyP", "BracketLeft", "BracketRight", "Backslash"},
+		{"KeyA", "KeyS", "KeyD", "KeyF", "KeyG", "KeyH", "KeyJ", "KeyK", "KeyL", "Semicolon", "Quote"},
+		{"KeyZ", "KeyX", "KeyC", "KeyV", "KeyB", "KeyN", "KeyM", "Comma", "Period", "Slash"},
+	}
+
+	// isoScanCodes are the VS Code scan codes for the character keys of an ISO
+	// keyboard. The Backslash key is next to the enter key and the
+	// IntlBackslash key is next to the left shift key.
+	isoScanCodes = [][]string{
+		{"Backquote", "Digit1", "Digit2", "Digit3", "Digit4", "Digit5", "Digit6", "Digit7", "Digit8", "Digit9", "Digit0", "Minus", "Equal"},
+		{"KeyQ", "KeyW", "KeyE", "KeyR", "KeyT", "KeyY", "KeyU", "KeyI", "KeyO", "KeyP", "BracketLeft", "BracketRight"},
+		{"KeyA", "KeyS", "KeyD", "KeyF", "KeyG", "KeyH", "KeyJ", "KeyK", "KeyL", "Semicolon", "Quote", "Backslash"},
+		{"IntlBackslash", "KeyZ", "KeyX", "KeyC", "KeyV", "KeyB", "KeyN", "KeyM", "Comma", "Period", "Slash"},
+	}
+
+	usLayout = mustKeyboardLayout(&keyboardLayoutDefinition{
+		name:        "us",
+		description: "US QWERTY",
+		keyCodes:    true,
+		scanCodes:   ansiScanCodes,
+		rows: []string{
+			"`1234567890-=",
+			`qwertyuiop[]\`,
+			`asdfghjkl;'`,
+			`zxcvbnm,./`,
+		},
+		shiftedRows: []string{
+			`~!@#$%^&*()_+`,
+			`QWERTYUIOP{}|`,
+			`ASDFGHJKL:"`,
+			`ZXCVBNM<>?`,
+		},
+	})
+
+	ukLayout = mustKeyboardLayout(&keyboardLayoutDefinition{
+		name:        "uk",
+		description: "UK QWERTY",
+		scanCodes:   isoScanCodes,
+		rows: []string{
+			"`1234567890-=",
+			`qwertyuiop[]`,
+			`asdfghjkl;'#`,
+			`\zxcvbnm,./`,
+		},
+		shiftedRows: []string{
+			`¬!"£$%^&*()_+`,
+			`QWERTYUIOP{}`,
+			`ASDFGHJKL:@~`,
+			`|ZXCVBNM<>?`,
+		},
+		altGr: map[string]string{
+			"Backquote": "¦",
+			"Digit4":    "€",
+			"KeyA":      "á",
+			"KeyE":      "é",
+			"KeyI":      "í",
+			"KeyO":      "ó",
+			"KeyU":      "ú",
+		},
+	})
+
+	germanLayout = mustKeyboardLayout(&keyboardLayoutDefinition{
+		name:        "de",
+		description: "German QWERTZ",
+		scanCodes:   isoScanCodes,
+		rows: []string{
+			// `^` (Backquote) and `´` (Equal) are dead keys.
+			` 1234567890ß `,
+			`qwertzuiopü+`,
+			`asdfghjklöä#`,
+			`<yxcvbnm,.-`,
+		},
+		shiftedRows: []string{
+			// shift+Equal (`) is a dead key.
+			`°!"§$%&/()=? `,
+			`QWERTZUIOPÜ*`,
+			`ASDFGHJKLÖÄ'`,
+			`>YXCVBNM;:_`,
+		},
+		altGr: map[string]string{
+			"Digit2":        "²",
+			"Digit3":        "³",
+			"Digit7":        "{",
+			"Digit8":        "[",
+			"Digit9":        "]",
+			"Digit0":        "}",
+			"Minus":         `\`,
+			"KeyQ":          "@",
+			"KeyE":          "€",
+			"BracketRight":  "~",
+			"IntlBackslash": "|",
+			"KeyM":          "µ",
+		},
+	})
+
+	dvorakLayout = mustKeyboardLayout(&keyboardLayoutDefinition{
+		name:        "dvorak",
+		description: "US Dvorak",
+		scanCodes:   ansiScanCodes,
+		rows: []string{
+			"`1234567890[]",
+			`',.pyfgcrl/=\`,
+			`aoeuidhtns-`,
+			`;qjkxbmwvz`,
+		},
+		shiftedRows: []string{
+			`~!@#$%^&*(){}`,
+			`"<>PYFGCRL?+|`,
+			`AOEUIDHTNS_`,
+			`:QJKXBMWVZ`,
+		},
+	})
+
+	colemakLayout = mustKeyboardLayout(&keyboardLayoutDefinition{
+		name:        "colemak",
+		description: "Colemak",
+		scanCodes:   ansiScanCodes,
+		rows: []string{
+			"`1234567890-=",
+			`qwfpgjluy;[]\`,
+			`arstdhneio'`,
+			`zxcvbkm,./`,
+		},
+		shiftedRows: []string{
+			`~!@#$%^&*()_+`,
+			`QWFPGJLUY:{}|`,
+			`ARSTDHNEIO"`,
+			`ZXCVBKM<>?`,
+		},
+	})
+
+	// keyboardLayouts are all of the supported keyboard layouts.
+	keyboardLayouts = []*KeyboardLayout{
+		usLayout,
+		ukLayout,
+		germanLayout,
+		dvorakLayout,
+		colemakLayout,
+	}
+
+	// defaultKeyboardLayout is the layout used when none is selected.
+	defaultKeyboardLayout = usLayout
+)
+
+// KeyboardLayout describes the text typed by each (physical) character key.
+type KeyboardLayout struct {
+	Name        string
+	Description string
+	Keys        []*LayoutKey
+	// keyCodes indicates whether keybindings should use the key codes (e.g.
+	// `a`) rather than scan codes (e.g. `[KeyA]`). VS Code only has key codes
+	// for the US character set, so this is only set for the US layout.
+	keyCodes bool
+}
+
+// LayoutKey is a single physical key in a keyboard layout. Empty text values
+// indicate that nothing is typed for that modifier combination.
+type LayoutKey struct {
+	// ScanCode is the VS Code scan code (e.g. `KeyQ`) of the key.
+	ScanCode string
+	Text     string
+	Shifted  string
+	// AltGr is the text typed when holding AltGr (which VS Code treats as
+	// `ctrl+alt`).
+	AltGr string
+}
+
+type keyboardLayoutDefinition struct {
+	name        string
+	description string
+	keyCodes    bool
+	scanCodes   [][]string
+	rows        []string
+	shiftedRows []string
+	altGr       map[string]string
+}
+
+func mustKeyboardLayout(kld *keyboardLayoutDefinition) *KeyboardLayout {
+	kl, err := newKeyboardLayout(kld)
+	if err != nil {
+		panic(err)
+	}
+	return kl
+}
+
+func newKeyboardLayout(kld *keyboardLayoutDefinition) (*KeyboardLayout, error) {
+	if len(kld.rows) != len(kld.scanCodes) || len(kld.shiftedRows) != len(kld.scanCodes) {
+		return nil, fmt.Errorf("keyboard layout %q has %d rows and %d shifted rows; expected %d", kld.name, len(kld.rows), len(kld.shiftedRows), len(kld.scanCodes))
+	}
+
+	layoutText := func(r rune) string {
+		if r == noText {
+			return ""
+		}
+		return string(r)
+	}
+
+	kl := &KeyboardLayout{
+		Name:        kld.name,
+		Description: kld.description,
+		keyCodes:    kld.keyCodes,
+	}
+	used := map[string]bool{}
+	for i, codes := range kld.scanCodes {
+		row, shiftedRow := []rune(kld.rows[i]), []rune(kld.shiftedRows[i])
+		if len(row) != len(codes) || len(shiftedRow) != len(codes) {
+			return nil, fmt.Errorf("keyboard layout %q row %d has %d keys and %d shifted keys; expected %d", kld.name, i, len(row), len(shiftedRow), len(codes))
+		}
+		for j, code := range codes {
+			kl.Keys = append(kl.Keys, &LayoutKey{
+				ScanCode: code,
+				Text:     layoutText(row[j]),
+				Shifted:  layoutText(shiftedRow[j]),
+				AltGr:    kld.altGr[code],
+			})
+			used[code] = true
+		}
+	}
+
+	var unknown []string
+	for code := range kld.altGr {
+		if !used[code] {
+			unknown = append(unknown, code)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("keyboard layout %q has AltGr text for unknown scan codes: %v", kld.name, unknown)
+	}
+	return kl, nil
+}
+
+// key returns the (unmodified) keybinding key for the layout key.
+func (kl *KeyboardLayout) key(lk *LayoutKey) Key {
+	if kl.keyCodes {
+		return Key(lk.Text)
+	}
+	return Key(fmt.Sprintf("[%s]", lk.ScanCode))
+}
+
+var (
+	// keyModifierOrder is the order of modifiers in physical keys.
+	keyModifierOrder = map[string]int{
+		"ctrl":  0,
+		"shift": 1,
+		"alt":   2,
+		"meta":  3,
+		"cmd":   3,
+		"win":   3,
+	}
+)
+
+// physicalKey returns the keybinding key with its key codes resolved to the
+// scan codes of the layout keys that type them (e.g. `ctrl+e` is
+// `ctrl+[KeyD]` in the Dvorak layout) and its modifiers in a consistent order.
+// Key codes that aren't typed by a layout key (e.g. `enter`) are unchanged.
+func (kl *KeyboardLayout) physicalKey(k Key) string {
+	var chords []string
+	for _, chord := range strings.Fields(string(k)) {
+		// Ignore the last character so that the `+` key (e.g. `ctrl++`) is handled.
+		var modifiers []string
+		key := chord
+		if i := strings.LastIndex(chord[:len(chord)-1], "+"); i >= 0 {
+			modifiers, key = strings.Split(chord[:i], "+"), chord[i+1:]
+		}
+		sort.SliceStable(modifiers, func(i, j int) bool {
+			return keyModifierOrder[modifiers[i]] < keyModifierOrder[modifiers[j]]
+		})
+
+		if !strings.HasPrefix(key, "[") {
+			for _, lk := range kl.Keys {
+				if lk.Text == key {
+					key = fmt.Sprintf("[%s]", lk.ScanCode)
+					break
+				}
+			}
+		}
+		chords = append(chords, strings.Join(append(modifiers, key), "+"))
+	}
+	return strings.Join(chords, " ")
+}
+
+// overrideConflicts returns the groog.type overrides that are bound to the
+// same physical key as one of the provided keybinding definitions.
+func (kl *KeyboardLayout) overrideConflicts(defs map[Key]map[string]*KB) []string {
+	physicalKeys := map[string]Key{}
+	for k := range defs {
+		physicalKeys[kl.physicalKey(k)] = k
+	}
+
+	var conflicts []string
+	for k := range kl.typeOverrides() {
+		pk := kl.physicalKey(k)
+		if dk, ok := physicalKeys[pk]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("%s layout key %s conflicts with keybinding %s (physical key %s)", kl.Name, k, dk, pk))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// typeOverrides returns the groog.type keybinding overrides for every key
+// (and modifier combination) that types text in the layout.
+func (kl *KeyboardLayout) typeOverrides() map[Key]string {
+	overrides := map[Key]string{}
+	for _, lk := range kl.Keys {
+		k := kl.key(lk)
+		if lk.Text != "" {
+			overrides[k] = lk.Text
+		}
+		if lk.Shifted != "" {
+			overrides[shift(k)] = lk.Shifted
+		}
+		if lk.AltGr != "" {
+			overrides[ctrl(alt(k))] = lk.AltGr
+		}
+	}
+	return overrides
+}
+
+// findKeyboardLayout returns the keyboard layout with the provided name.
+func findKeyboardLayout(name string) (*KeyboardLayout, error) {
+	for _, kl := range keyboardLayouts {
+		if strings.EqualFold(kl.Name, name) {
+			return kl, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown keyboard layout %q; expected one of %v", name, keyboardLayoutNames())
+}
+
+// selectKeyboardLayout returns the keyboard layout with the provided name,
+// falling back to the layout in the keyboardLayoutEnv environment variable and
+// then the default layout.
+func selectKeyboardLayout(name string) (*KeyboardLayout, error) {
+	if name == "" {
+		name = os.Getenv(keyboardLayoutEnv)
+	}
+	if name == "" {
+		return defaultKeyboardLayout, nil
+	}
+	return findKeyboardLayout(name)
+}
+
+// keyboardLayoutNames returns the names of the supported keyboard layouts.
+func keyboardLayoutNames() []string {
+	var names []string
+	for _, kl := range keyboardLayouts {
+		names = append(names, kl.Name)
+	}
+	return names
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPhysicalKey(t *testing.T) {
+	for _, test := range []struct {
+		layout *KeyboardLayout
+		key    Key
+		want   string
+	}{
+		{usLayout, "a", "[KeyA]"},
+		{usLayout, "ctrl+a", "ctrl+[KeyA]"},
+		{usLayout, "alt+ctrl+e", "ctrl+alt+[KeyE]"},
+		{usLayout, "alt+shift+ctrl+/", "ctrl+shift+alt+[Slash]"},
+		{usLayout, "ctrl+x s", "ctrl+[KeyX] [KeyS]"},
+		{usLayout, "enter", "enter"},
+		{usLayout, "ctrl+enter", "ctrl+enter"},
+		{usLayout, "shift+[KeyQ]", "shift+[KeyQ]"},
+		{dvorakLayout, "ctrl+e", "ctrl+[KeyD]"},
+		{dvorakLayout, "[KeyD]", "[KeyD]"},
+		{germanLayout, "z", "[KeyY]"},
+		{germanLayout, "ctrl++", "ctrl+[BracketRight]"},
+		// Key codes that aren't typed by the layout are unchanged.
+		{germanLayout, "ctrl+[", "ctrl+["},
+	} {
+		t.Run(test.layout.Name+" "+string(test.key), func(t *testing.T) {
+			if got := test.layout.physicalKey(test.key); got != test.want {
+				t.Errorf("physicalKey(%q) returned %q; want %q", test.key, got, test.want)
+			}
+		})
+	}
+}
+
+func TestOverrideConflicts(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		layout *KeyboardLayout
+		defs   []Key
+		want   []string
+	}{
+		{
+			name:   "no conflicts",
+			layout: usLayout,
+			defs:   []Key{"alt+a", "ctrl+a", "enter"},
+		},
+		{
+			name:   "same key",
+			layout: usLayout,
+			defs:   []Key{"shift+a"},
+			want:   []string{"us layout key shift+a conflicts with keybinding shift+a (physical key shift+[KeyA])"},
+		},
+		{
+			name:   "key code and scan code",
+			layout: dvorakLayout,
+			defs:   []Key{"e"},
+			want:   []string{"dvorak layout key [KeyD] conflicts with keybinding e (physical key [KeyD])"},
+		},
+		{
+			name:   "AltGr key",
+			layout: ukLayout,
+			defs:   []Key{"alt+ctrl+e", "ctrl+alt+x"},
+			want:   []string{"uk layout key ctrl+alt+[KeyE] conflicts with keybinding alt+ctrl+e (physical key ctrl+alt+[KeyE])"},
+		},
+		{
+			name:   "moved key",
+			layout: germanLayout,
+			defs:   []Key{"shift+z", "shift+[KeyZ]"},
+			want: []string{
+				"de layout key shift+[KeyY] conflicts with keybinding shift+z (physical key shift+[KeyY])",
+				"de layout key shift+[KeyZ] conflicts with keybinding shift+[KeyZ] (physical key shift+[KeyZ])",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			defs := map[Key]map[string]*KB{}
+			for _, k := range test.defs {
+				defs[k] = map[string]*KB{always.value(): kb("groog.test")}
+			}
+			got := test.layout.overrideConflicts(defs)
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("overrideConflicts() returned:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestKeyboardLayoutsDontConflict(t *testing.T) {
+	for _, kl := range keyboardLayouts {
+		t.Run(kl.Name, func(t *testing.T) {
+			if conflicts := kl.overrideConflicts(kbDefinitions); len(conflicts) > 0 {
+				t.Errorf("kbDefinitions conflict with the %s layout:\n%s", kl.Name, strings.Join(conflicts, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// keymapUnit is the width (in pixels) of a standard key.
+	keymapUnit = 48.0
+	// keymapGap is the space (in pixels) between adjacent keys.
+	keymapGap = 4.0
+	// keymapTitleHeight is the height (in pixels) of the title above the keyboard.
+	keymapTitleHeight = 32.0
+)
+
+var (
+	// keymapLayers are the modifier layers drawn by the keymap-svg command.
+	keymapLayers = []*keymapLayer{
+		{"none", "none.svg", "", nil},
+		{"ctrl", "ctrl.svg", "ctrl+", []string{"ctrl"}},
+		{"alt", "alt.svg", "alt+", []string{"alt"}},
+		{"ctrl+shift", "ctrl-shift.svg", "ctrl+shift+", []string{"ctrl", "shift"}},
+		{"ctrl+x leader", "ctrl-x.svg", "ctrl+x ", nil},
+		{"ctrl+z leader", "ctrl-z.svg", "ctrl+z ", nil},
+	}
+
+	// keymapKeys is a US ANSI keyboard (positions are in keymapUnit units).
+	keymapKeys = concat(
+		keymapRow(0, 0, 1, escape),
+		keymapRow(2, 0, 1, "f1", "f2", "f3", "f4"),
+		keymapRow(6.5, 0, 1, "f5", "f6", "f7", "f8"),
+		keymapRow(11, 0, 1, "f9", "f10", "f11", "f12"),
+		keymapRow(0, 1.25, 1, strings.Split("`1234567890-=", "")...),
+		keymapRow(13, 1.25, 2, backspace),
+		keymapRow(0, 2.25, 1.5, tab),
+		keymapRow(1.5, 2.25, 1, strings.Split("qwertyuiop[]", "")...),
+		keymapRow(13.5, 2.25, 1.5, `\`),
+		keymapRow(0, 3.25, 1.75, "capslock"),
+		keymapRow(1.75, 3.25, 1, strings.Split("asdfghjkl;'", "")...),
+		keymapRow(12.75, 3.25, 2.25, enter),
+		keymapRow(0, 4.25, 2.25, "shift"),
+		keymapRow(2.25, 4.25, 1, strings.Split("zxcvbnm,./", "")...),
+		keymapRow(12.25, 4.25, 2.75, "shift"),
+		keymapRow(0, 5.25, 1.25, "ctrl", "meta", "alt"),
+		keymapRow(3.75, 5.25, 6.25, space),
+		keymapRow(10, 5.25, 1.25, "alt", "meta", "menu", "ctrl"),
+		keymapRow(15.25, 1.25, 1, insert, home, pageup),
+		keymapRow(15.25, 2.25, 1, delete, end, pagedown),
+		keymapRow(16.25, 4.25, 1, up),
+		keymapRow(15.25, 5.25, 1, left, down, right),
+	)
+
+	// keymapModifiers are the keys that can't be bound on their own.
+	keymapModifiers = map[string]bool{
+		"capslock": true,
+		"shift":    true,
+		"ctrl":     true,
+		"alt":      true,
+		"meta":     true,
+		"menu":     true,
+	}
+
+	keymapLabels = map[string]string{
+		backspace:  "Bksp",
+		escape:     "Esc",
+		enter:      "Enter",
+		tab:        "Tab",
+		space:      "Space",
+		pageup:     "PgUp",
+		pagedown:   "PgDn",
+		insert:     "Ins",
+		delete:     "Del",
+		home:       "Home",
+		end:        "End",
+		up:         "↑",
+		down:       "↓",
+		left:       "←",
+		right:      "→",
+		"capslock": "Caps",
+		"shift":    "Shift",
+		"ctrl":     "Ctrl",
+		"alt":      "Alt",
+		"meta":     "Meta",
+		"menu":     "Menu",
+	}
+
+	// keymapColors are the key colors indexed by the number of contexts that
+	// bind the key (the last color is used for any larger number).
+	keymapColors = []string{"#ffffff", "#c6dbef", "#6baed6", "#3182bd", "#08519c"}
+)
+
+// keymapLayer is a set of keys that share the same modifiers (or leader key).
+type keymapLayer struct {
+	name string
+	// file is the name of the layer's svg file.
+	file string
+	// prefix is prepended to a key to get the keybinding key.
+	prefix string
+	// held are the modifier keys that are held down for the layer.
+	held []string
+}
+
+// keymapKey is a key on the keyboard diagram.
+type keymapKey struct {
+	key   string
+	x     float64
+	y     float64
+	width float64
+}
+
+// keymapRow returns a row of keys (with the same width) that starts at (x, y).
+func keymapRow(x, y, width float64, keys ...string) []*keymapKey {
+	var kks []*keymapKey
+	for i, key := range keys {
+		kks = append(kks, &keymapKey{key, x + float64(i)*width, y, width})
+	}
+	return kks
+}
+
+func concat[T any](slices ...[]T) []T {
+	var r []T
+	for _, s := range slices {
+		r = append(r, s...)
+	}
+	return r
+}
+
+// bindings returns the bindings (by when clause) for the key in the layer.
+func (kl *keymapLayer) bindings(defs map[Key]map[string]*KB, key string) map[string]*KB {
+	m := map[string]*KB{}
+	for when, kb := range defs[Key(kl.prefix+key)] {
+		// A nil binding is ignored by kbDefsToBindings.
+		if kb != nil {
+			m[when] = kb
+		}
+	}
+	return m
+}
+
+// leader returns whether the key (in the layer) is the start of a chord.
+func (kl *keymapLayer) leader(defs map[Key]map[string]*KB, key string) bool {
+	for k := range defs {
+		if strings.HasPrefix(k.ToString(), kl.prefix+key+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// undrawnKeys returns the keys in the layer that aren't on the keyboard
+// diagram.
+func (kl *keymapLayer) undrawnKeys(defs map[Key]map[string]*KB) []string {
+	drawn := map[string]bool{}
+	for _, kk := range keymapKeys {
+		drawn[kl.prefix+kk.key] = true
+	}
+
+	var undrawn []string
+	for k := range defs {
+		base := strings.TrimPrefix(k.ToString(), kl.prefix)
+		if !strings.HasPrefix(k.ToString(), kl.prefix) || strings.ContainsAny(base, "+ ") || drawn[k.ToString()] {
+			continue
+		}
+		undrawn = append(undrawn, k.ToString())
+	}
+	sort.Strings(undrawn)
+	return undrawn
+}
+
+// contextCount returns the number of contexts that bind the key (removals
+// don't count).
+func contextCount(bindings map[string]*KB) int {
+	var count int
+	for _, b := range bindings {
+		if !strings.HasPrefix(b.Command, "-") {
+			count++
+		}
+	}
+	return count
+}
+
+// primaryCommand returns the command that runs in the least specific context
+// (preferring commands over removals).
+func primaryCommand(bindings map[string]*KB) string {
+	whens := sortedKeys(bindings)
+	sort.SliceStable(whens, func(i, j int) bool {
+		iRemoval, jRemoval := strings.HasPrefix(bindings[whens[i]].Command, "-"), strings.HasPrefix(bindings[whens[j]].Command, "-")
+		if iRemoval != jRemoval {
+			return jRemoval
+		}
+		return len(whens[i]) < len(whens[j])
+	})
+	if len(whens) == 0 {
+		return ""
+	}
+	return bindings[whens[0]].Command
+}
+
+// shortCommand drops the command's namespace (e.g. `groog.cursorUp` becomes
+// `cursorUp`).
+func shortCommand(cmd string) string {
+	prefix := ""
+	if strings.HasPrefix(cmd, "-") {
+		prefix, cmd = "-", cmd[1:]
+	}
+	if strings.HasPrefix(cmd, "groog.multiCommand.") {
+		return prefix + "multiCommand"
+	}
+	return prefix + cmd[strings.LastIndex(cmd, ".")+1:]
+}
+
+func keymapLabel(key string) string {
+	if l, ok := keymapLabels[key]; ok {
+		return l
+	}
+	return strings.ToUpper(key)
+}
+
+// truncateLabel shortens the label so it fits in the provided number of
+// characters.
+func truncateLabel(label string, n int) string {
+	runes := []rune(label)
+	if len(runes) <= n {
+		return label
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// keymapSVG returns an svg image of the keyboard for the layer along with the
+// number of bound keys and bindable keys. Each key is colored by the number of
+// contexts that bind it and labeled with its primary command.
+func keymapSVG(kl *keymapLayer, defs map[Key]map[string]*KB) (string, int, int) {
+	held := map[string]bool{}
+	for _, h := range kl.held {
+		held[h] = true
+	}
+
+	var keys strings.Builder
+	var bound, bindable int
+	var width, height float64
+	for _, kk := range keymapKeys {
+		x, y := kk.x*keymapUnit, kk.y*keymapUnit+keymapTitleHeight
+		w, h := kk.width*keymapUnit-keymapGap, keymapUnit-keymapGap
+		if x+w > width {
+			width = x + w
+		}
+		if y+h > height {
+			height = y + h
+		}
+
+		if keymapModifiers[kk.key] {
+			fill, textClass := "#d9d9d9", "dark"
+			if held[kk.key] {
+				fill, textClass = "#636363", "light"
+			}
+			fmt.Fprintf(&keys, "  <g class=\"%s\">\n", textClass)
+			fmt.Fprintf(&keys, "    <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" rx=\"4\" fill=\"%s\" stroke=\"#969696\" />\n", x, y, w, h, fill)
+			fmt.Fprintf(&keys, "    <text x=\"%g\" y=\"%g\" class=\"key\">%s</text>\n", x+4, y+12, xmlEscape(keymapLabel(kk.key)))
+			fmt.Fprintf(&keys, "  </g>\n")
+			continue
+		}
+
+		bindable++
+		bindings := kl.bindings(defs, kk.key)
+		count := contextCount(bindings)
+		if count > 0 {
+			bound++
+		}
+		color := keymapColors[len(keymapColors)-1]
+		if count < len(keymapColors) {
+			color = keymapColors[count]
+		}
+		textClass := "dark"
+		if count >= 3 {
+			textClass = "light"
+		}
+		stroke, strokeWidth := "#969696", 1
+		label := truncateLabel(shortCommand(primaryCommand(bindings)), int((w-6)/4.5))
+		if kl.leader(defs, kk.key) {
+			stroke, strokeWidth = "#e6550d", 3
+			if label == "" {
+				label = "leader"
+			}
+		}
+
+		var title strings.Builder
+		title.WriteString(kl.prefix + kk.key)
+		for _, when := range sortedKeys(bindings) {
+			context := when
+			if context == "" {
+				context = "(always)"
+			}
+			fmt.Fprintf(&title, "\n%s: %s", context, bindings[when].Command)
+		}
+
+		fmt.Fprintf(&keys, "  <g class=\"%s\">\n", textClass)
+		fmt.Fprintf(&keys, "    <title>%s</title>\n", xmlEscape(title.String()))
+		fmt.Fprintf(&keys, "    <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" rx=\"4\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%d\" />\n", x, y, w, h, color, stroke, strokeWidth)
+		fmt.Fprintf(&keys, "    <text x=\"%g\" y=\"%g\" class=\"key\">%s</text>\n", x+4, y+12, xmlEscape(keymapLabel(kk.key)))
+		if label != "" {
+			fmt.Fprintf(&keys, "    <text x=\"%g\" y=\"%g\" class=\"command\">%s</text>\n", x+4, y+h-6, xmlEscape(label))
+		}
+		fmt.Fprintf(&keys, "  </g>\n")
+	}
+
+	// Legend
+	legendY := height + keymapGap*2
+	var legend strings.Builder
+	for i, color := range keymapColors {
+		label := fmt.Sprintf("%d contexts", i)
+		switch i {
+		case 0:
+			label = "free"
+		case 1:
+			label = "1 context"
+		case len(keymapColors) - 1:
+			label = fmt.Sprintf("%d+ contexts", i)
+		}
+		x := float64(i) * 2 * keymapUnit
+		fmt.Fprintf(&legend, "  <rect x=\"%g\" y=\"%g\" width=\"16\" height=\"16\" rx=\"2\" fill=\"%s\" stroke=\"#969696\" />\n", x, legendY, color)
+		fmt.Fprintf(&legend, "  <text x=\"%g\" y=\"%g\" class=\"legend\">%s</text>\n", x+20, legendY+12, label)
+	}
+	leaderX := float64(len(keymapColors)) * 2 * keymapUnit
+	fmt.Fprintf(&legend, "  <rect x=\"%g\" y=\"%g\" width=\"16\" height=\"16\" rx=\"2\" fill=\"#ffffff\" stroke=\"#e6550d\" stroke-width=\"3\" />\n", leaderX, legendY)
+	fmt.Fprintf(&legend, "  <text x=\"%g\" y=\"%g\" class=\"legend\">starts a chord</text>\n", leaderX+20, legendY+12)
+	height = legendY + 16
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%g\" height=\"%g\" viewBox=\"0 0 %g %g\">\n", width, height, width, height)
+	sb.WriteString("  <style>\n")
+	sb.WriteString("    text { font-family: sans-serif; fill: #252525; }\n")
+	sb.WriteString("    .light text { fill: #ffffff; }\n")
+	sb.WriteString("    .key { font-size: 11px; font-weight: bold; }\n")
+	sb.WriteString("    .command { font-size: 8px; }\n")
+	sb.WriteString("    .title { font-size: 16px; }\n")
+	sb.WriteString("    .legend { font-size: 12px; }\n")
+	sb.WriteString("  </style>\n")
+	fmt.Fprintf(&sb, "  <text x=\"0\" y=\"20\" class=\"title\">groog keybindings: %s (%d of %d keys bound)</text>\n", xmlEscape(kl.name), bound, bindable)
+	sb.WriteString(keys.String())
+	sb.WriteString(legend.String())
+	sb.WriteString("</svg>\n")
+	return sb.String(), bound, bindable
+}
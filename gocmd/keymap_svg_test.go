@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestKeymapSVG(t *testing.T) {
+	for _, kl := range keymapLayers {
+		t.Run(kl.name, func(t *testing.T) {
+			svg, _, _ := keymapSVG(kl, kbDefinitions)
+			checkGolden(t, fmt.Sprintf("keymap/%s", kl.file), svg)
+		})
+	}
+}
+
+func TestKeymapLayersDrawAllKeys(t *testing.T) {
+	for _, kl := range keymapLayers {
+		if undrawn := kl.undrawnKeys(kbDefinitions); len(undrawn) > 0 {
+			t.Errorf("keymap layer %q doesn't draw keys: %s", kl.name, strings.Join(undrawn, ", "))
+		}
+	}
+}
+
+func TestPrimaryCommand(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		bindings map[string]*KB
+		want     string
+	}{
+		{
+			name: "no bindings",
+		},
+		{
+			name: "always",
+			bindings: map[string]*KB{
+				"editorTextFocus": kb("groog.cursorRight"),
+				"":                kb("groog.find"),
+			},
+			want: "groog.find",
+		},
+		{
+			name: "least specific context",
+			bindings: map[string]*KB{
+				"groog.qmkMode && terminalFocus": kb("groog.terminal.find"),
+				"groog.qmkMode":                  kb("groog.find"),
+			},
+			want: "groog.find",
+		},
+		{
+			name: "prefers commands over removals",
+			bindings: map[string]*KB{
+				"":              kb("-workbench.action.terminal.focusFind"),
+				"groog.qmkMode": kb("groog.find"),
+			},
+			want: "groog.find",
+		},
+		{
+			name: "only removals",
+			bindings: map[string]*KB{
+				"": kb("-workbench.action.terminal.focusFind"),
+			},
+			want: "-workbench.action.terminal.focusFind",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := primaryCommand(test.bindings); got != test.want {
+				t.Errorf("primaryCommand() returned %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestShortCommand(t *testing.T) {
+	for _, test := range []struct {
+		cmd  string
+		want string
+	}{
+		{"groog.cursorUp", "cursorUp"},
+		{"workbench.action.files.save", "save"},
+		{"-workbench.action.terminal.focusFind", "-focusFind"},
+		{"groog.multiCommand.execute", "multiCommand"},
+		{"noop", "noop"},
+	} {
+		if got := shortCommand(test.cmd); got != test.want {
+			t.Errorf("shortCommand(%q) returned %q; want %q", test.cmd, got, test.want)
+		}
+	}
+}
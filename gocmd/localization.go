@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// See the following link for details on localizing package.json:
+// https://code.visualstudio.com/api/references/extension-manifest#localization
+
+const (
+	// translationsDir is the directory (relative to the groog root directory)
+	// that contains additional locales. Each `<locale>.json` file maps
+	// localization keys to translated strings and is written to
+	// `package.nls.<locale>.json`.
+	translationsDir = "translations"
+)
+
+var (
+	// localizedSchemaFields are the configuration property fields that are
+	// replaced with localization keys.
+	localizedSchemaFields = []string{
+		"description",
+		"markdownDescription",
+	}
+)
+
+// localizePackage returns a copy of the package whose command titles and
+// configuration titles and descriptions are replaced with `%key%` references,
+// along with the English strings for each key.
+func localizePackage(p *Package) (*Package, map[string]string) {
+	lp := *p
+	contributes := *p.Contributes
+	lp.Contributes = &contributes
+
+	nls := map[string]string{}
+	localize := func(key, value string) string {
+		nls[key] = value
+		return fmt.Sprintf("%%%s%%", key)
+	}
+
+	var commands []*Command
+	for _, c := range p.Contributes.Commands {
+		commands = append(commands, &Command{c.Command, localize(fmt.Sprintf("%s.title", c.Command), c.Title)})
+	}
+	lp.Contributes.Commands = commands
+
+	var configuration []*Configuration
+	for _, c := range p.Contributes.Configuration {
+		lc := &Configuration{
+			Title:      localize(fmt.Sprintf("configuration.%s.title", nlsKeyPart(c.Title)), c.Title),
+			Order:      c.Order,
+			Properties: map[string]map[string]interface{}{},
+		}
+		for name, schema := range c.Properties {
+			lc.Properties[name] = replaceSchemaStrings(name, schema, localize)
+		}
+		configuration = append(configuration, lc)
+	}
+	lp.Contributes.Configuration = configuration
+	return &lp, nls
+}
+
+// delocalizePackage replaces `%key%` references in the package with the
+// provided strings (the inverse of localizePackage).
+func delocalizePackage(p *Package, nls map[string]string) {
+	delocalize := func(s string) string {
+		if strings.HasPrefix(s, "%") && strings.HasSuffix(s, "%") && len(s) > 2 {
+			if v, ok := nls[s[1:len(s)-1]]; ok {
+				return v
+			}
+		}
+		return s
+	}
+
+	for _, c := range p.Contributes.Commands {
+		c.Title = delocalize(c.Title)
+	}
+	for _, c := range p.Contributes.Configuration {
+		c.Title = delocalize(c.Title)
+		for name, schema := range c.Properties {
+			c.Properties[name] = replaceSchemaStrings(name, schema, func(_, s string) string {
+				return delocalize(s)
+			})
+		}
+	}
+}
+
+// replaceSchemaStrings returns a copy of the schema with each localized field
+// replaced by the result of f, including the fields of nested array items and
+// object properties. The key passed to f identifies the field (e.g.
+// `groog.typos.items.whole.description`).
+func replaceSchemaStrings(key string, schema map[string]interface{}, f func(key, value string) string) map[string]interface{} {
+	r := map[string]interface{}{}
+	for k, v := range schema {
+		r[k] = v
+	}
+	for _, field := range localizedSchemaFields {
+		if s, ok := r[field].(string); ok {
+			r[field] = f(fmt.Sprintf("%s.%s", key, field), s)
+		}
+	}
+
+	if items, ok := r["items"].(map[string]interface{}); ok {
+		r["items"] = replaceSchemaStrings(fmt.Sprintf("%s.items", key), items, f)
+	}
+	if props, ok := r["properties"].(map[string]interface{}); ok {
+		rp := map[string]interface{}{}
+		for name, v := range props {
+			if ps, ok := v.(map[string]interface{}); ok {
+				rp[name] = replaceSchemaStrings(fmt.Sprintf("%s.%s", key, name), ps, f)
+			} else {
+				rp[name] = v
+			}
+		}
+		r["properties"] = rp
+	}
+	return r
+}
+
+// nlsKeyPart converts a title into a localization key segment (e.g. `QMK
+// Settings` becomes `qmkSettings`).
+func nlsKeyPart(title string) string {
+	words := strings.Fields(title)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+		} else {
+			words[i] = upperFirst(strings.ToLower(w))
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// nlsFiles returns the package.nls.json file and a package.nls.<locale>.json
+// file for each translation in the translations directory.
+func nlsFiles(root string, nls map[string]string) ([]*generatedFile, error) {
+	b, err := marshalJson(nls)
+	if err != nil {
+		return nil, err
+	}
+	files := []*generatedFile{{"package.nls.json", b}}
+
+	translations, err := filepath.Glob(filepath.Join(root, translationsDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translations: %v", err)
+	}
+	sort.Strings(translations)
+
+	for _, t := range translations {
+		contents, err := os.ReadFile(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read translation file: %v", err)
+		}
+
+		var strs map[string]string
+		if err := json.Unmarshal(contents, &strs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal translation file %s: %v", t, err)
+		}
+
+		var unknown []string
+		for k := range strs {
+			if _, ok := nls[k]; !ok {
+				unknown = append(unknown, k)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return nil, fmt.Errorf("%s contains unknown localization keys: %v", t, unknown)
+		}
+
+		b, err := marshalJson(strs)
+		if err != nil {
+			return nil, err
+		}
+		locale := strings.TrimSuffix(filepath.Base(t), ".json")
+		files = append(files, &generatedFile{fmt.Sprintf("package.nls.%s.json", locale), b})
+	}
+	return files, nil
+}
+
+// loadNLS reads a package.nls.json file. An empty map is returned if the file
+// doesn't exist.
+func loadNLS(filename string) (map[string]string, error) {
+	b, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nls file: %v", err)
+	}
+	return unmarshalNLS(b)
+}
+
+func unmarshalNLS(b []byte) (map[string]string, error) {
+	nls := map[string]string{}
+	if err := json.Unmarshal(b, &nls); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal nls file: %v", err)
+	}
+	return nls, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLocalizePackage(t *testing.T) {
+	schema := NewJSONArray(
+		NewJSONObject(map[string]*JSONSchema{
+			"words": NewJSONObject(nil, JSONMarkdownDescription("Map of typos")),
+			"count": NewJSONInteger(),
+		}, JSONDescription("A correction")),
+		JSONMarkdownDescription("List of corrections"),
+	)
+	p := &Package{
+		Contributes: &Contribution{
+			Commands: []*Command{
+				{"groog.find", "Groog find"},
+			},
+			Configuration: []*Configuration{
+				{
+					Title: "Typos",
+					Order: 1,
+					Properties: map[string]map[string]interface{}{
+						"groog.typos": schema.evaluate(),
+					},
+				},
+			},
+		},
+	}
+	want := compactJson(p.Contributes)
+
+	lp, nls := localizePackage(p)
+
+	wantNLS := map[string]string{
+		"configuration.typos.title":                   "Typos",
+		"groog.find.title":                            "Groog find",
+		"groog.typos.markdownDescription":             "List of corrections",
+		"groog.typos.items.description":               "A correction",
+		"groog.typos.items.words.markdownDescription": "Map of typos",
+	}
+	if compactJson(nls) != compactJson(wantNLS) {
+		t.Errorf("localizePackage() returned strings %s; want %s", compactJson(nls), compactJson(wantNLS))
+	}
+
+	wantLocalized := `{"items":{"description":"%groog.typos.items.description%","properties":{"count":{"type":"integer"},"words":{"markdownDescription":"%groog.typos.items.words.markdownDescription%","properties":{},"type":"object"}},"type":"object"},"markdownDescription":"%groog.typos.markdownDescription%","type":"array"}`
+	if got := compactJson(lp.Contributes.Configuration[0].Properties["groog.typos"]); got != wantLocalized {
+		t.Errorf("localizePackage() returned schema %s; want %s", got, wantLocalized)
+	}
+
+	// The original package should not be modified.
+	if got := compactJson(p.Contributes); got != want {
+		t.Errorf("localizePackage() modified the package: %s; want %s", got, want)
+	}
+
+	delocalizePackage(lp, nls)
+	if got := compactJson(lp.Contributes); got != want {
+		t.Errorf("delocalizePackage() returned %s; want %s", got, want)
+	}
+}
@@ -6,10 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
-	"runtime"
-	"strconv"
-	"strings"
+	"time"
 
 	"github.com/leep-frog/command/command"
 	"github.com/leep-frog/command/commander"
@@ -35,100 +32,680 @@ func (*cli) Setup() []string { return nil }
 func (*cli) Changed() bool   { return false }
 
 var (
-	versionRegex = regexp.MustCompile("^(\\s*Version:\\s*[\"`])([0-9\\.]+)([\"`],)$")
-	runtimeNode  = commander.RuntimeCaller()
+	runtimeNode = commander.RuntimeCaller()
 )
 
 func (c *cli) Node() command.Node {
-	versionSectionArg := commander.OptionalArg[int]("VERSION", "Version section offset (0 for smallest, 1 for middle, 2 for major)", commander.Default(0), commander.Between(0, 2, true))
+	versionSectionArg := commander.OptionalArg[int]("VERSION", "Version section offset (0 for smallest, 1 for middle, 2 for major)", commander.Default(patchVersion), commander.Between(patchVersion, majorVersion, true))
+	prereleaseArg := commander.OptionalArg[string]("PRERELEASE", "Pre-release tag (e.g. beta) for the new version")
+	typosFileArg := commander.OptionalArg[string]("TYPOS_JSON", "JSON file containing a list of typo corrections to validate against the groog.typos schema")
+	dryRunFlag := commander.BoolFlag("dry-run", 'n', "Print the generated files instead of writing them")
+	checkFlag := commander.BoolFlag("check", 'c', "Fail (and print a diff) if the generated files differ from the files on disk")
+	keyboardLayoutFlag := commander.Flag[string]("layout", 'l', fmt.Sprintf("Keyboard layout used for the groog.type overrides (one of %v; defaults to $%s or %s)", keyboardLayoutNames(), keyboardLayoutEnv, defaultKeyboardLayout.Name))
+	// keyboardLayout returns the keyboard layout selected by the layout flag
+	// (or the keyboardLayoutEnv environment variable).
+	keyboardLayout := func(d *command.Data) (*KeyboardLayout, error) {
+		return selectKeyboardLayout(keyboardLayoutFlag.Get(d))
+	}
+	keybindingsFileArg := commander.OptionalArg[string]("OUTPUT", "File to write the keybindings to", commander.Default("keybindings.json"))
+	profileFileArg := commander.OptionalArg[string]("OUTPUT", "File to write the profile to", commander.Default(fmt.Sprintf("%s.code-profile", profileName)))
+	profilePlatformFlag := commander.Flag[string]("platform", 'p', fmt.Sprintf("Platform to import the profile keybindings for (one of %v)", sortedKeys(profilePlatforms)), commander.Default("windows"))
+	missingExtensionsArg := commander.ListArg[string]("EXTENSION_ID", "Companion extensions to report broken keybindings for (defaults to all)", 0, commander.UnboundedList)
+	defaultKeybindingsArg := commander.Arg[string]("DEFAULT_KEYBINDINGS_JSON", "VS Code's default keybindings (from the `Preferences: Open Default Keyboard Shortcuts (JSON)` command)")
+	vscodeVersionArg := commander.OptionalArg[string]("VSCODE_VERSION", "The VS Code version the default keybindings are from", commander.Default(vscodeEngineVersion()))
+	simulateKeyArg := commander.Arg[string]("KEY", "The key (or chord) to press (e.g. `ctrl+k` or `ctrl+x s`)")
+	simulateContextFlag := commander.Flag[string]("ctx", 'x', "Comma-separated context key values (e.g. `groog.context.findMode=true,resourceLangId=go`)")
+	simulateDefaultsFlag := commander.Flag[string]("defaults", 'd', "VS Code's default keybindings (from the `Preferences: Open Default Keyboard Shortcuts (JSON)` command) to resolve the key against")
+	exportFormatArg := commander.Arg[string]("FORMAT", fmt.Sprintf("Editor to export the keybindings for (one of %v)", sortedKeys(keybindingExporters)))
+	exportFileArg := commander.OptionalArg[string]("OUTPUT", "File to write the exported keybindings to (defaults to groog.xml, groog.lua, or groog.el)")
+	keymapDirArg := commander.OptionalArg[string]("OUTPUT_DIR", "Directory to write the keymap svg files to", commander.Default("keymap"))
+	oldPackageArg := commander.OptionalArg[string]("OLD_PACKAGE_JSON", "The package.json file to compare against (defaults to the package.json at HEAD)")
+	snippetNameArg := commander.Arg[string]("NAME", "Name of the snippet to render")
+	snippetValuesArg := commander.ListArg[string]("VALUES", "Tabstop and variable values (e.g. 1=Name or TM_FILENAME=file.go)", 0, commander.UnboundedList)
+	snippetFormatArg := commander.Arg[string]("FORMAT", "Format of the snippets to import (ultisnips, yasnippet, or textmate)")
+	snippetSourceArg := commander.Arg[string]("PATH", "Snippet file or directory to import")
+	snippetLanguageArg := commander.OptionalArg[string]("LANGUAGE", "VS Code language identifier for the snippets (inferred from the source files if not provided)")
+	snippetFileNameArg := commander.OptionalArg[string]("FILE_NAME", "Name of the generated snippet file (defaults to FORMAT-LANGUAGE)")
 
 	return commander.SerialNodes(
 		runtimeNode,
 		&commander.BranchNode{
 			Branches: map[string]command.Node{
 				"update u": commander.SerialNodes(
+					commander.FlagProcessor(
+						keyboardLayoutFlag,
+					),
 					versionSectionArg,
+					prereleaseArg,
 					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
-						_, fileName, _, ok := runtime.Caller(0)
-						if !ok {
-							return o.Stderrf("failed to get runtime.Caller")
+						root := groogRoot(d)
+						layout, err := keyboardLayout(d)
+						if err != nil {
+							return o.Err(err)
 						}
 
-						// Go two directories up (to groog root)
-						packageFile := filepath.Join(filepath.Dir(fileName), "package.go")
+						source := groogVersionSource(root)
+						oldVersion, err := source.read()
+						if err != nil {
+							return o.Err(err)
+						}
 
-						b, err := os.ReadFile(packageFile)
+						newVersion, err := oldVersion.bump(versionSectionArg.Get(d), prereleaseArg.Get(d))
 						if err != nil {
-							return o.Annotatef(err, "failed to read package.go")
+							return o.Err(err)
 						}
 
-						contents := strings.Split(string(b), "\n")
-						var newContents []string
-						var replaced int
-						var newVersion string
-						for _, line := range contents {
-							m := versionRegex.FindStringSubmatch(line)
-							if len(m) > 0 {
-								replaced++
-								prefix, version, suffix := m[1], m[2], m[3]
-								versionParts := strings.Split(version, ".")
+						// Load the previous package.json before it is overwritten so the
+						// changes can be recorded in the changelog.
+						oldPackage, err := loadPackageJSON(filepath.Join(root, "package.json"))
+						if err != nil {
+							return o.Err(err)
+						}
 
-								indexToChange := len(versionParts) - 1 - versionSectionArg.Get(d)
+						newPackage, err := c.regeneratePackageJson(o, d, newVersion.String(), layout)
+						if err != nil {
+							return err
+						}
 
-								vNum, err := strconv.Atoi(versionParts[indexToChange])
-								if err != nil {
-									return o.Annotatef(err, "failed to convert version")
-								}
+						normalizedPackage, err := normalizePackage(newPackage)
+						if err != nil {
+							return o.Err(err)
+						}
 
-								// Clear out smaller versions
-								for i := indexToChange; i < len(versionParts); i++ {
-									versionParts[i] = "0"
-								}
-								versionParts[indexToChange] = fmt.Sprintf("%d", vNum+1)
-								newVersion = strings.Join(versionParts, ".")
-								line = fmt.Sprintf("%s%s%s", prefix, newVersion, suffix)
+						section := changelogSection(newVersion, time.Now(), diffContributions(oldPackage, normalizedPackage, nil, nil))
+						if err := updateChangelog(filepath.Join(root, "CHANGELOG.md"), section); err != nil {
+							return o.Err(err)
+						}
+						o.Stdoutln("Successfully updated CHANGELOG.md")
+
+						// The version source is updated last so a failure above doesn't
+						// leave the version out of sync with the generated files.
+						msg, err := source.write(newVersion)
+						if err != nil {
+							return o.Err(err)
+						}
+						o.Stdoutln(msg)
+						return nil
+					}},
+				),
+				"keybindings": commander.SerialNodes(
+					commander.FlagProcessor(
+						keyboardLayoutFlag,
+					),
+					keybindingsFileArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						layout, err := keyboardLayout(d)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						b, err := marshalJson(kbDefsToBindings(layout))
+						if err != nil {
+							return o.Err(err)
+						}
+
+						if err := os.WriteFile(keybindingsFileArg.Get(d), b, 0644); err != nil {
+							return o.Annotatef(err, "failed to write keybindings file")
+						}
+						o.Stdoutf("Successfully wrote keybindings to %s\n", keybindingsFileArg.Get(d))
+						return nil
+					}},
+				),
+				"profile": commander.SerialNodes(
+					commander.FlagProcessor(
+						keyboardLayoutFlag,
+						profilePlatformFlag,
+					),
+					profileFileArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						layout, err := keyboardLayout(d)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						version, err := currentVersion(groogRoot(d))
+						if err != nil {
+							return o.Err(err)
+						}
+						p, err := groogPackage(version.String(), layout)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						profile, err := groogProfile(p, layout, profilePlatformFlag.Get(d))
+						if err != nil {
+							return o.Err(err)
+						}
+
+						b, err := marshalJson(profile)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						if err := os.WriteFile(profileFileArg.Get(d), b, 0644); err != nil {
+							return o.Annotatef(err, "failed to write profile file")
+						}
+						o.Stdoutf("Successfully wrote profile to %s\n", profileFileArg.Get(d))
+						return nil
+					}},
+				),
+				"extensions": commander.SerialNodes(
+					commander.FlagProcessor(
+						keyboardLayoutFlag,
+					),
+					missingExtensionsArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						layout, err := keyboardLayout(d)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						report, err := extensionReport(kbDefsToBindings(layout), missingExtensionsArg.Get(d))
+						if err != nil {
+							return o.Err(err)
+						}
+						o.Stdout(report)
+						return nil
+					}},
+				),
+				"builtins": commander.SerialNodes(
+					defaultKeybindingsArg,
+					vscodeVersionArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						contents, err := os.ReadFile(defaultKeybindingsArg.Get(d))
+						if err != nil {
+							return o.Annotatef(err, "failed to read default keybindings file")
+						}
+
+						vb, err := parseDefaultKeybindings(contents, vscodeVersionArg.Get(d))
+						if err != nil {
+							return o.Err(err)
+						}
+
+						b, err := marshalJson(vb)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						if err := os.WriteFile(filepath.Join(groogRoot(d), "gocmd", vscodeBuiltinsFile), b, 0644); err != nil {
+							return o.Annotatef(err, "failed to write %s", vscodeBuiltinsFile)
+						}
+						o.Stdoutf("Successfully wrote %d commands and %d context keys to %s\n", len(vb.Commands), len(vb.ContextKeys), vscodeBuiltinsFile)
+						return nil
+					}},
+				),
+				"removals": commander.SerialNodes(
+					commander.FlagProcessor(
+						keyboardLayoutFlag,
+					),
+					defaultKeybindingsArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						layout, err := keyboardLayout(d)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						contents, err := os.ReadFile(defaultKeybindingsArg.Get(d))
+						if err != nil {
+							return o.Annotatef(err, "failed to read default keybindings file")
+						}
+						defaults, err := unmarshalDefaultKeybindings(contents)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						report, unmatched := removalReport(kbDefsToBindings(layout), defaults)
+						o.Stdout(report)
+						if unmatched > 0 {
+							return o.Stderrf("%d removal keybinding(s) don't match a default keybinding\n", unmatched)
+						}
+						o.Stdoutln("All removal keybindings match a default keybinding")
+						return nil
+					}},
+				),
+				"simulate": commander.SerialNodes(
+					commander.FlagProcessor(
+						keyboardLayoutFlag,
+						simulateContextFlag,
+						simulateDefaultsFlag,
+					),
+					simulateKeyArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						layout, err := keyboardLayout(d)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						ctx, err := parseWhenContextValues(simulateContextFlag.Get(d))
+						if err != nil {
+							return o.Err(err)
+						}
+
+						var defaults []*Keybinding
+						if defaultsFile := simulateDefaultsFlag.Get(d); defaultsFile != "" {
+							contents, err := os.ReadFile(defaultsFile)
+							if err != nil {
+								return o.Annotatef(err, "failed to read default keybindings file")
+							}
+							if defaults, err = unmarshalDefaultKeybindings(contents); err != nil {
+								return o.Err(err)
 							}
-							newContents = append(newContents, line)
 						}
 
-						if replaced == 0 {
-							return o.Stderrf("Made no replacements")
+						s, err := simulateKeypress(simulateKeyArg.Get(d), ctx, defaults, kbDefsToBindings(layout))
+						if err != nil {
+							return o.Err(err)
+						}
+						for _, w := range s.warnings {
+							o.Stderrf("Warning: %s\n", w)
+						}
+						o.Stdout(s.String())
+						return nil
+					}},
+				),
+				"export": commander.SerialNodes(
+					commander.FlagProcessor(
+						keyboardLayoutFlag,
+					),
+					exportFormatArg,
+					exportFileArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						layout, err := keyboardLayout(d)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						e, err := findKeybindingExporter(exportFormatArg.Get(d))
+						if err != nil {
+							return o.Err(err)
 						}
 
-						if err := os.WriteFile(packageFile, []byte(strings.Join(newContents, "\n")), 0644); err != nil {
-							return o.Annotatef(err, "failed to write new contents to package.go")
+						contents, report := exportKeybindings(e, kbDefsToBindings(layout))
+						file := exportFileArg.Get(d)
+						if file == "" {
+							file = e.filename()
+						}
+						if err := os.WriteFile(file, []byte(contents), 0644); err != nil {
+							return o.Annotatef(err, "failed to write exported keybindings")
+						}
+						o.Stderr(report.String())
+						o.Stdoutf("Exported keybindings to %s\n", file)
+						return nil
+					}},
+				),
+				"keymap-svg": commander.SerialNodes(
+					keymapDirArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						dir := keymapDirArg.Get(d)
+						if err := os.MkdirAll(dir, 0755); err != nil {
+							return o.Annotatef(err, "failed to create keymap directory")
 						}
 
-						o.Stdoutln("Successfully updated to new version:", newVersion)
+						for _, kl := range keymapLayers {
+							for _, k := range kl.undrawnKeys(kbDefinitions) {
+								o.Stderrf("Warning: %s is not on the keyboard diagram\n", k)
+							}
 
-						return c.regeneratePackageJson(o, d, newVersion)
+							svg, bound, bindable := keymapSVG(kl, kbDefinitions)
+							file := filepath.Join(dir, kl.file)
+							if err := os.WriteFile(file, []byte(svg), 0644); err != nil {
+								return o.Annotatef(err, "failed to write keymap svg")
+							}
+							o.Stdoutf("Wrote %s (%d of %d keys bound)\n", file, bound, bindable)
+						}
+						return nil
+					}},
+				),
+				"diff": commander.SerialNodes(
+					commander.FlagProcessor(
+						keyboardLayoutFlag,
+					),
+					oldPackageArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						root := groogRoot(d)
+
+						var oldPackage *Package
+						var oldSnippets snippetLoader
+						if oldFile := oldPackageArg.Get(d); oldFile != "" {
+							p, err := loadPackageJSON(oldFile)
+							if err != nil {
+								return o.Err(err)
+							}
+							oldPackage, oldSnippets = p, dirSnippetLoader(filepath.Dir(oldFile))
+						} else {
+							p, err := gitPackageJSON(root, "HEAD")
+							if err != nil {
+								return o.Err(err)
+							}
+							oldPackage, oldSnippets = p, gitSnippetLoader(root, "HEAD")
+						}
+
+						version, err := currentVersion(root)
+						if err != nil {
+							return o.Err(err)
+						}
+						layout, err := keyboardLayout(d)
+						if err != nil {
+							return o.Err(err)
+						}
+						p, err := groogPackage(version.String(), layout)
+						if err != nil {
+							return o.Err(err)
+						}
+						newPackage, err := normalizePackage(p)
+						if err != nil {
+							return o.Err(err)
+						}
+
+						diff := diffContributions(oldPackage, newPackage, oldSnippets, generatedSnippetLoader)
+						o.Stdout(diff.report())
+
+						section := diff.suggestedBump()
+						oldVersion, err := parseVersion(oldPackage.Version)
+						if err != nil {
+							o.Stdoutf("Suggested version bump: %s\n", versionSectionNames[section])
+							return nil
+						}
+						newVersion, err := oldVersion.bump(section, "")
+						if err != nil {
+							return o.Err(err)
+						}
+						o.Stdoutf("Suggested version bump: %s (%s → %s)\n", versionSectionNames[section], oldVersion, newVersion)
+						return nil
+					}},
+				),
+				"snippet": &commander.BranchNode{
+					Branches: map[string]command.Node{
+						"lint": commander.SerialNodes(
+							&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+								if issues := lintSnippetFiles(SnippetFiles); len(issues) > 0 {
+									for _, issue := range issues {
+										o.Stderrln(issue)
+									}
+									return o.Stderrf("Found %d snippet issue(s)\n", len(issues))
+								}
+								o.Stdoutln("No snippet issues found")
+								return nil
+							}},
+						),
+						"render": commander.SerialNodes(
+							snippetNameArg,
+							snippetValuesArg,
+							&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+								s, err := findSnippet(snippetNameArg.Get(d))
+								if err != nil {
+									return o.Err(err)
+								}
+
+								values, err := parseSnippetValues(snippetValuesArg.Get(d))
+								if err != nil {
+									return o.Err(err)
+								}
+
+								text, err := renderSnippet(s, values)
+								if err != nil {
+									return o.Err(err)
+								}
+								o.Stdoutln(text)
+								return nil
+							}},
+						),
+						"import": commander.SerialNodes(
+							snippetFormatArg,
+							snippetSourceArg,
+							snippetLanguageArg,
+							snippetFileNameArg,
+							&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+								sf, issues, err := importSnippets(snippetFormatArg.Get(d), snippetSourceArg.Get(d), snippetLanguageArg.Get(d), snippetFileNameArg.Get(d))
+								for _, issue := range issues {
+									o.Stderrln(issue)
+								}
+								if err != nil {
+									return o.Err(err)
+								}
+
+								goFile, err := writeImportedSnippetFile(groogRoot(d), sf)
+								if err != nil {
+									return o.Err(err)
+								}
+								o.Stdoutf("Imported %d snippet(s) into %s (registered in %s)\n", len(sf.Snippets), sf.Path, goFile)
+								if len(issues) > 0 {
+									o.Stdoutf("%d construct(s) could not be translated (see above)\n", len(issues))
+								}
+								o.Stdoutln("Run `vs-package` to regenerate package.json")
+								return nil
+							}},
+						),
+					},
+				},
+				"validate": commander.SerialNodes(
+					typosFileArg,
+					&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
+						if err := validateConfiguration(); err != nil {
+							return o.Err(err)
+						}
+
+						if typosFile := typosFileArg.Get(d); typosFile != "" {
+							if err := validateJSONFile(typosSchema(), typosFile); err != nil {
+								return o.Err(err)
+							}
+						}
+
+						o.Stdoutln("Configuration is valid")
+						return nil
 					}},
 				),
 			},
 			Default: commander.SerialNodes(
+				commander.FlagProcessor(
+					dryRunFlag,
+					checkFlag,
+					keyboardLayoutFlag,
+				),
 				&commander.ExecutorProcessor{func(o command.Output, d *command.Data) error {
-					return c.regeneratePackageJson(o, d, "")
+					layout, err := keyboardLayout(d)
+					if err != nil {
+						return o.Err(err)
+					}
+
+					switch {
+					case dryRunFlag.Get(d) && checkFlag.Get(d):
+						return o.Stderrf("--dry-run and --check cannot be used together\n")
+					case dryRunFlag.Get(d):
+						return c.printGeneratedFiles(o, d, layout)
+					case checkFlag.Get(d):
+						return c.checkGeneratedFiles(o, d, layout)
+					}
+					_, err = c.regeneratePackageJson(o, d, "", layout)
+					return err
 				}},
 			),
 		},
 	)
 }
 
-func (c *cli) regeneratePackageJson(o command.Output, d *command.Data, versionOverride string) error {
-	filename := filepath.Join(filepath.Dir(filepath.Dir(runtimeNode.Get(d))), "package.json")
+// groogRoot returns the root directory of the groog repository.
+func groogRoot(d *command.Data) string {
+	return filepath.Dir(filepath.Dir(runtimeNode.Get(d)))
+}
+
+// generatedFile is a file (relative to the groog root directory) that is
+// produced by the generator.
+type generatedFile struct {
+	Path     string
+	Contents []byte
+}
+
+// generateFiles generates the package.json, package.nls.json, settings, and
+// snippet files for the keyboard layout (and returns the unlocalized
+// package). The generated package.json sections are merged into the existing
+// package.json in the provided root directory (see packageOwnedSections).
+func generateFiles(root, versionOverride string, layout *KeyboardLayout) (*Package, []*generatedFile, error) {
+	if err := validateConfiguration(); err != nil {
+		return nil, nil, err
+	}
+
+	version := versionOverride
+	if version == "" {
+		v, err := currentVersion(root)
+		if err != nil {
+			return nil, nil, err
+		}
+		version = v.String()
+	}
+
+	p, err := groogPackage(version, layout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	localized, nls := localizePackage(p)
+	generated, err := marshalJson(localized)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existing, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read package.json: %v", err)
+	}
+
+	b, err := mergePackageJSON(existing, generated)
+	if err != nil {
+		return nil, nil, err
+	}
+	files := []*generatedFile{{"package.json", b}}
+
+	settings, err := generatedSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+	files = append(files, &generatedFile{generatedSettingsFile, settings})
+
+	localeFiles, err := nlsFiles(root, nls)
+	if err != nil {
+		return nil, nil, err
+	}
+	files = append(files, localeFiles...)
+
+	for _, sf := range SnippetFiles {
+		m, err := sf.json()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		b, err := marshalJson(m)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, &generatedFile{sf.Path, b})
+	}
+	return p, files, nil
+}
+
+// regeneratePackageJson writes all of the generated files and
+// returns the generated package.
+func (c *cli) regeneratePackageJson(o command.Output, d *command.Data, versionOverride string, layout *KeyboardLayout) (*Package, error) {
+	root := groogRoot(d)
 
-	p := groogPackage(versionOverride)
+	p, files, err := generateFiles(root, versionOverride, layout)
+	if err != nil {
+		return nil, o.Err(err)
+	}
+	if err := c.warnUnknownBuiltins(o, p); err != nil {
+		return nil, err
+	}
 
-	b, err := marshalJson(p)
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(f.Path)), f.Contents, 0644); err != nil {
+			return nil, o.Annotatef(err, "failed to write %s", f.Path)
+		}
+	}
+	o.Stdoutln("Successfully updated package.json, localization, and snippet files")
+	return p, nil
+}
+
+// checkGeneratedFiles prints a unified diff for every generated file that
+// differs from the file on disk, and returns an error if any do.
+func (c *cli) checkGeneratedFiles(o command.Output, d *command.Data, layout *KeyboardLayout) error {
+	root := groogRoot(d)
+
+	p, files, err := generateFiles(root, "", layout)
+	if err != nil {
+		return o.Err(err)
+	}
+	if err := c.warnUnknownBuiltins(o, p); err != nil {
+		return err
+	}
+
+	// The generated files use the current version, so make sure the version on
+	// disk was recorded (otherwise, the diff is just the version change).
+	if _, err := os.Stat(filepath.Join(root, "package.json")); err == nil {
+		existingPackage, err := loadPackageJSON(filepath.Join(root, "package.json"))
+		if err != nil {
+			return o.Err(err)
+		}
+		if err := checkRecordedVersion(groogVersionSource(root), existingPackage.Version); err != nil {
+			return o.Err(err)
+		}
+	}
+
+	var outOfDate int
+	for _, f := range files {
+		existing, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(f.Path)))
+		if err != nil && !os.IsNotExist(err) {
+			return o.Annotatef(err, "failed to read %s", f.Path)
+		}
+
+		if diff := unifiedDiff(fmt.Sprintf("a/%s", f.Path), fmt.Sprintf("b/%s", f.Path), string(existing), string(f.Contents)); diff != "" {
+			outOfDate++
+			o.Stdout(diff)
+		}
+	}
+
+	if outOfDate > 0 {
+		return o.Stderrf("%d generated file(s) are out of date; run `vs-package` to regenerate them\n", outOfDate)
+	}
+	o.Stdoutln("All generated files are up to date")
+	return nil
+}
+
+// printGeneratedFiles prints the generated files without writing them.
+func (c *cli) printGeneratedFiles(o command.Output, d *command.Data, layout *KeyboardLayout) error {
+	p, files, err := generateFiles(groogRoot(d), "", layout)
 	if err != nil {
+		return o.Err(err)
+	}
+	if err := c.warnUnknownBuiltins(o, p); err != nil {
 		return err
 	}
 
-	if err := os.WriteFile(filename, b, 0644); err != nil {
-		return fmt.Errorf("failed to write json to output file: %v", err)
+	for i, f := range files {
+		if i > 0 {
+			o.Stdoutln()
+		}
+		o.Stdoutf("==> %s <==\n", f.Path)
+		o.Stdout(string(f.Contents))
 	}
+	return nil
+}
 
-	o.Stdoutln("Successfully updated package.json")
+// warnUnknownBuiltins prints a warning (to stderr) for every command and
+// context key in the package's keybindings that isn't in the VS Code catalog
+// (see vscodeBuiltinsFile).
+func (c *cli) warnUnknownBuiltins(o command.Output, p *Package) error {
+	vb, err := loadVSCodeBuiltins()
+	if err != nil {
+		return o.Err(err)
+	}
+
+	warnings, err := builtinWarnings(p.Contributes.Keybindings, vb)
+	if err != nil {
+		return o.Err(err)
+	}
+	for _, w := range warnings {
+		o.Stderrf("Warning: %s\n", w)
+	}
 	return nil
 }
 
@@ -136,11 +713,11 @@ func (c *cli) regeneratePackageJson(o command.Output, d *command.Data, versionOv
 // Without this, sometimes json marshaling writes \u0026 and sometimes
 // it writes `&` (for ampersand and other html characters like `<`)
 // This logic ensures we always write the actual characters and not their coded ones.
-func marshalJson(p *Package) ([]byte, error) {
+func marshalJson(v interface{}) ([]byte, error) {
 	unindentedBuffer := bytes.NewBuffer([]byte{})
 	unicodeLiteralEncoder := json.NewEncoder(unindentedBuffer)
 	unicodeLiteralEncoder.SetEscapeHTML(false)
-	if err := unicodeLiteralEncoder.Encode(p); err != nil {
+	if err := unicodeLiteralEncoder.Encode(v); err != nil {
 		return nil, fmt.Errorf("failed to marshal json: %v", err)
 	}
 
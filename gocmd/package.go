@@ -1,17 +1,21 @@
 package main
 
-import "golang.org/x/exp/slices"
+import (
+	"fmt"
 
-func groogPackage(versionOverride string) *Package {
+	"golang.org/x/exp/slices"
+)
+
+func groogPackage(version string, layout *KeyboardLayout) (*Package, error) {
 	p := &Package{
 		Name:        "groog",
 		DisplayName: "groog",
 		Description: "",
-		Version:     "2.7.92",
+		Version:     version,
 		Publisher:   "groogle",
 		Main:        "./bundled-out/extension.js",
 		Engines: map[string]string{
-			"vscode": "^1.96.0",
+			"vscode": vscodeEngine,
 		},
 		ExtensionKind: []string{
 			"ui",
@@ -71,20 +75,28 @@ func groogPackage(versionOverride string) *Package {
 		ActivationEvents: []string{},
 	}
 
-	if versionOverride != "" {
-		p.Version = versionOverride
+	configuration, err := groogConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate configuration: %v", err)
+	}
+
+	configurationDefaults, err := groogConfigurationDefaults()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate configuration defaults: %v", err)
 	}
 
 	p.Contributes = &Contribution{
-		Commands:      CustomCommands,
-		Keybindings:   kbDefsToBindings(),
-		Configuration: groogConfiguration(),
-		Snippets:      Snippets,
+		Commands:              CustomCommands,
+		Keybindings:           kbDefsToBindings(layout),
+		Configuration:         configuration,
+		ConfigurationDefaults: configurationDefaults,
+		Snippets:              snippetContributions(),
 	}
+	p.ExtensionPack = extensionPack()
 	sortFunc(p.Contributes.Commands, func(a, b *Command) bool {
 		return a.Command < b.Command
 	})
-	return p
+	return p, nil
 }
 
 func sortFunc[T any](ts []T, f func(a, b T) bool) {
@@ -115,6 +127,9 @@ type Package struct {
 	ActivationEvents []string          `json:"activationEvents"`
 	Contributes      *Contribution     `json:"contributes"`
 	ExtensionKind    []string          `json:"extensionKind"`
+	// ExtensionPack contains the companion extensions that are installed with
+	// groog (see companionExtensions).
+	ExtensionPack []string `json:"extensionPack,omitempty"`
 }
 
 func (p *Package) sort() {
@@ -142,10 +157,12 @@ type Repository struct {
 }
 
 type Contribution struct {
-	Commands      []*Command     `json:"commands"`
-	Keybindings   []*Keybinding  `json:"keybindings"`
-	Configuration *Configuration `json:"configuration"`
-	Snippets      []*Snippet     `json:"snippets"`
+	Commands      []*Command       `json:"commands"`
+	Keybindings   []*Keybinding    `json:"keybindings"`
+	Configuration []*Configuration `json:"configuration"`
+	// Map from language identifier (e.g. `[go]`) to default setting values for that language.
+	ConfigurationDefaults map[string]map[string]interface{} `json:"configurationDefaults,omitempty"`
+	Snippets              []*Snippet                        `json:"snippets"`
 }
 
 type Keybinding struct {
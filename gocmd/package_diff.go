@@ -0,0 +1,554 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// loadPackageJSON reads a package.json file into a Package (replacing
+// localization keys with the strings in the sibling package.nls.json file).
+func loadPackageJSON(filename string) (*Package, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package json: %v", err)
+	}
+	p, err := unmarshalPackageJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	nls, err := loadNLS(filepath.Join(filepath.Dir(filename), "package.nls.json"))
+	if err != nil {
+		return nil, err
+	}
+	delocalizePackage(p, nls)
+	return p, nil
+}
+
+// gitPackageJSON reads the package.json file at the provided revision.
+func gitPackageJSON(root, revision string) (*Package, error) {
+	b, err := gitShow(root, revision, "package.json")
+	if err != nil {
+		return nil, err
+	}
+	p, err := unmarshalPackageJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Older revisions may not be localized.
+	if nlsBytes, err := gitShow(root, revision, "package.nls.json"); err == nil {
+		nls, err := unmarshalNLS(nlsBytes)
+		if err != nil {
+			return nil, err
+		}
+		delocalizePackage(p, nls)
+	}
+	return p, nil
+}
+
+func unmarshalPackageJSON(b []byte) (*Package, error) {
+	p := &Package{}
+	if err := json.Unmarshal(b, p); err != nil {
+		// Older package.json files contribute a single configuration object
+		// rather than a list of configuration sections.
+		converted, ok := convertLegacyConfiguration(b)
+		if !ok {
+			return nil, fmt.Errorf("failed to unmarshal package json: %v", err)
+		}
+		p = &Package{}
+		if err := json.Unmarshal(converted, p); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal package json: %v", err)
+		}
+	}
+	if p.Contributes == nil {
+		p.Contributes = &Contribution{}
+	}
+	return p, nil
+}
+
+// convertLegacyConfiguration wraps a single `contributes.configuration`
+// object in a list. False is returned if there is nothing to convert.
+func convertLegacyConfiguration(b []byte) ([]byte, bool) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, false
+	}
+	contributes, ok := m["contributes"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	configuration, ok := contributes["configuration"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	contributes["configuration"] = []interface{}{configuration}
+
+	converted, err := json.Marshal(m)
+	if err != nil {
+		return nil, false
+	}
+	return converted, true
+}
+
+// normalizePackage round-trips a generated package through json so it can be
+// compared with a package that was read from a file.
+func normalizePackage(p *Package) (*Package, error) {
+	b, err := marshalJson(p)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalPackageJSON(b)
+}
+
+// gitShow returns the contents of the file (relative to the repository root)
+// at the provided revision.
+func gitShow(root, revision, path string) ([]byte, error) {
+	b, err := exec.Command("git", "-C", root, "show", fmt.Sprintf("%s:%s", revision, path)).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git show %s:%s failed: %v: %s", revision, path, err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, fmt.Errorf("git show %s:%s failed: %v", revision, path, err)
+	}
+	return b, nil
+}
+
+// snippetLoader returns the contents of a contributed snippet file.
+type snippetLoader func(path string) ([]byte, error)
+
+func gitSnippetLoader(root, revision string) snippetLoader {
+	return func(path string) ([]byte, error) {
+		return gitShow(root, revision, path)
+	}
+}
+
+// dirSnippetLoader loads snippet files relative to the provided directory.
+func dirSnippetLoader(dir string) snippetLoader {
+	return func(path string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	}
+}
+
+// generatedSnippetLoader returns the contents of the snippet files in SnippetFiles.
+func generatedSnippetLoader(path string) ([]byte, error) {
+	for _, sf := range SnippetFiles {
+		if sf.Path == path {
+			m, err := sf.json()
+			if err != nil {
+				return nil, err
+			}
+			return marshalJson(m)
+		}
+	}
+	return nil, fmt.Errorf("unknown snippet file %q", path)
+}
+
+// contributionDiff contains the differences between the contributions of two
+// packages.
+type contributionDiff struct {
+	AddedCommands      []*Command
+	RemovedCommands    []*Command
+	RetitledCommands   []*retitledCommand
+	AddedKeybindings   []*Keybinding
+	RemovedKeybindings []*Keybinding
+	// ChangedKeybindings are key and when-clause combinations whose bindings changed.
+	ChangedKeybindings  []*changedKeybinding
+	AddedSettings       []string
+	RemovedSettings     []string
+	ChangedSettings     []*changedSetting
+	AddedSnippetFiles   []*Snippet
+	RemovedSnippetFiles []*Snippet
+	// ChangedSnippets is a list of snippet changes (e.g. `snippets/go-test.json: changed "Go Test"`).
+	ChangedSnippets []string
+	// Issues is a list of problems encountered while computing the diff.
+	Issues []string
+}
+
+type retitledCommand struct {
+	Command  string
+	OldTitle string
+	NewTitle string
+}
+
+type changedKeybinding struct {
+	Key  string
+	When string
+	Old  []*Keybinding
+	New  []*Keybinding
+}
+
+type changedSetting struct {
+	Name string
+	// Fields are the schema fields that changed (e.g. `default`).
+	Fields []string
+}
+
+// diffContributions compares the contributions of the two packages. If the
+// snippet loaders are provided, then the contents of snippet files that are
+// contributed by both packages are compared as well.
+func diffContributions(old, new *Package, oldSnippets, newSnippets snippetLoader) *contributionDiff {
+	d := &contributionDiff{}
+	d.diffCommands(old, new)
+	d.diffKeybindings(old, new)
+	d.diffSettings(old, new)
+	d.diffSnippets(old, new, oldSnippets, newSnippets)
+	return d
+}
+
+func (d *contributionDiff) diffCommands(old, new *Package) {
+	oldCommands, newCommands := commandsByID(old), commandsByID(new)
+	for _, id := range sortedKeys(newCommands) {
+		oc, ok := oldCommands[id]
+		if !ok {
+			d.AddedCommands = append(d.AddedCommands, newCommands[id])
+		} else if oc.Title != newCommands[id].Title {
+			d.RetitledCommands = append(d.RetitledCommands, &retitledCommand{id, oc.Title, newCommands[id].Title})
+		}
+	}
+	for _, id := range sortedKeys(oldCommands) {
+		if _, ok := newCommands[id]; !ok {
+			d.RemovedCommands = append(d.RemovedCommands, oldCommands[id])
+		}
+	}
+}
+
+func (d *contributionDiff) diffKeybindings(old, new *Package) {
+	oldKBs, newKBs := keybindingsByKeyWhen(old), keybindingsByKeyWhen(new)
+	for _, id := range sortedKeys(newKBs) {
+		nkbs := newKBs[id]
+		okbs, ok := oldKBs[id]
+		switch {
+		case !ok:
+			d.AddedKeybindings = append(d.AddedKeybindings, nkbs...)
+		case !slices.Equal(keybindingActions(okbs), keybindingActions(nkbs)):
+			d.ChangedKeybindings = append(d.ChangedKeybindings, &changedKeybinding{nkbs[0].Key, nkbs[0].When, okbs, nkbs})
+		}
+	}
+	for _, id := range sortedKeys(oldKBs) {
+		if _, ok := newKBs[id]; !ok {
+			d.RemovedKeybindings = append(d.RemovedKeybindings, oldKBs[id]...)
+		}
+	}
+}
+
+func (d *contributionDiff) diffSettings(old, new *Package) {
+	oldSettings, newSettings := settingsByName(old), settingsByName(new)
+	for _, name := range sortedKeys(newSettings) {
+		oldSchema, ok := oldSettings[name]
+		if !ok {
+			d.AddedSettings = append(d.AddedSettings, name)
+			continue
+		}
+
+		newSchema := newSettings[name]
+		fields := map[string]bool{}
+		for k := range oldSchema {
+			fields[k] = true
+		}
+		for k := range newSchema {
+			fields[k] = true
+		}
+		var changed []string
+		for _, f := range sortedKeys(fields) {
+			if !jsonEqual(oldSchema[f], newSchema[f]) {
+				changed = append(changed, f)
+			}
+		}
+		if len(changed) > 0 {
+			d.ChangedSettings = append(d.ChangedSettings, &changedSetting{name, changed})
+		}
+	}
+	for _, name := range sortedKeys(oldSettings) {
+		if _, ok := newSettings[name]; !ok {
+			d.RemovedSettings = append(d.RemovedSettings, name)
+		}
+	}
+}
+
+func (d *contributionDiff) diffSnippets(old, new *Package, oldSnippets, newSnippets snippetLoader) {
+	oldFiles, newFiles := snippetsByPath(old), snippetsByPath(new)
+	for _, path := range sortedKeys(newFiles) {
+		of, ok := oldFiles[path]
+		if !ok {
+			d.AddedSnippetFiles = append(d.AddedSnippetFiles, newFiles[path])
+			continue
+		}
+		if of.Language != newFiles[path].Language {
+			d.ChangedSnippets = append(d.ChangedSnippets, fmt.Sprintf("%s: language changed from %q to %q", path, of.Language, newFiles[path].Language))
+		}
+		if oldSnippets != nil && newSnippets != nil {
+			d.diffSnippetFile(path, oldSnippets, newSnippets)
+		}
+	}
+	for _, path := range sortedKeys(oldFiles) {
+		if _, ok := newFiles[path]; !ok {
+			d.RemovedSnippetFiles = append(d.RemovedSnippetFiles, oldFiles[path])
+		}
+	}
+}
+
+func (d *contributionDiff) diffSnippetFile(path string, oldSnippets, newSnippets snippetLoader) {
+	var defs []map[string]*snippetJSON
+	for _, load := range []snippetLoader{oldSnippets, newSnippets} {
+		b, err := load(path)
+		if err != nil {
+			d.Issues = append(d.Issues, fmt.Sprintf("failed to load snippet file %s: %v", path, err))
+			return
+		}
+		m, err := unmarshalSnippetJSON(b)
+		if err != nil {
+			d.Issues = append(d.Issues, fmt.Sprintf("failed to parse snippet file %s: %v", path, err))
+			return
+		}
+		defs = append(defs, m)
+	}
+
+	oldDefs, newDefs := defs[0], defs[1]
+	for _, name := range sortedKeys(newDefs) {
+		od, ok := oldDefs[name]
+		switch {
+		case !ok:
+			d.ChangedSnippets = append(d.ChangedSnippets, fmt.Sprintf("%s: added %q", path, name))
+		case !jsonEqual(od, newDefs[name]):
+			d.ChangedSnippets = append(d.ChangedSnippets, fmt.Sprintf("%s: changed %q", path, name))
+		}
+	}
+	for _, name := range sortedKeys(oldDefs) {
+		if _, ok := newDefs[name]; !ok {
+			d.ChangedSnippets = append(d.ChangedSnippets, fmt.Sprintf("%s: removed %q", path, name))
+		}
+	}
+}
+
+// unmarshalSnippetJSON unmarshals a snippet file whose prefixes or bodies
+// may be single strings.
+func unmarshalSnippetJSON(b []byte) (map[string]*snippetJSON, error) {
+	var m map[string]*struct {
+		Prefix      stringOrList `json:"prefix"`
+		Body        stringOrList `json:"body"`
+		Description string       `json:"description"`
+		Scope       string       `json:"scope"`
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	r := map[string]*snippetJSON{}
+	for name, s := range m {
+		r[name] = &snippetJSON{s.Prefix, s.Body, s.Description, s.Scope}
+	}
+	return r, nil
+}
+
+func (d *contributionDiff) empty() bool {
+	return len(d.AddedCommands)+len(d.RemovedCommands)+len(d.RetitledCommands)+
+		len(d.AddedKeybindings)+len(d.RemovedKeybindings)+len(d.ChangedKeybindings)+
+		len(d.AddedSettings)+len(d.RemovedSettings)+len(d.ChangedSettings)+
+		len(d.AddedSnippetFiles)+len(d.RemovedSnippetFiles)+len(d.ChangedSnippets) == 0
+}
+
+// suggestedBump returns the version section (see Version.bump) that should be
+// bumped for the changes. Removing (or changing the behavior of) existing
+// contributions is a major change, adding contributions is a minor change,
+// and everything else is a patch change.
+func (d *contributionDiff) suggestedBump() int {
+	if len(d.RemovedCommands)+len(d.RemovedKeybindings)+len(d.ChangedKeybindings)+len(d.RemovedSettings)+len(d.RemovedSnippetFiles) > 0 {
+		return majorVersion
+	}
+	for _, cs := range d.ChangedSettings {
+		if slices.Contains(cs.Fields, "type") {
+			return majorVersion
+		}
+	}
+	if len(d.AddedCommands)+len(d.AddedKeybindings)+len(d.AddedSettings)+len(d.AddedSnippetFiles) > 0 {
+		return minorVersion
+	}
+	return patchVersion
+}
+
+var (
+	versionSectionNames = map[int]string{
+		patchVersion: "patch",
+		minorVersion: "minor",
+		majorVersion: "major",
+	}
+)
+
+// report returns a human-readable report of the changes, grouped by
+// contribution type.
+func (d *contributionDiff) report() string {
+	var sb strings.Builder
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "%s:\n", title)
+		for _, l := range lines {
+			fmt.Fprintf(&sb, "  %s\n", l)
+		}
+	}
+
+	var commands []string
+	for _, c := range d.AddedCommands {
+		commands = append(commands, fmt.Sprintf("+ %s (%s)", c.Command, c.Title))
+	}
+	for _, c := range d.RemovedCommands {
+		commands = append(commands, fmt.Sprintf("- %s (%s)", c.Command, c.Title))
+	}
+	for _, c := range d.RetitledCommands {
+		commands = append(commands, fmt.Sprintf("~ %s: %q → %q", c.Command, c.OldTitle, c.NewTitle))
+	}
+	section("Commands", commands)
+
+	var kbs []string
+	for _, kb := range d.AddedKeybindings {
+		kbs = append(kbs, fmt.Sprintf("+ %s", keybindingString(kb)))
+	}
+	for _, kb := range d.RemovedKeybindings {
+		kbs = append(kbs, fmt.Sprintf("- %s", keybindingString(kb)))
+	}
+	for _, ckb := range d.ChangedKeybindings {
+		kbs = append(kbs, fmt.Sprintf("~ %s", ckb))
+	}
+	section("Keybindings", kbs)
+
+	var settings []string
+	for _, s := range d.AddedSettings {
+		settings = append(settings, fmt.Sprintf("+ %s", s))
+	}
+	for _, s := range d.RemovedSettings {
+		settings = append(settings, fmt.Sprintf("- %s", s))
+	}
+	for _, cs := range d.ChangedSettings {
+		settings = append(settings, fmt.Sprintf("~ %s (%s)", cs.Name, strings.Join(cs.Fields, ", ")))
+	}
+	section("Settings", settings)
+
+	var snippets []string
+	for _, s := range d.AddedSnippetFiles {
+		snippets = append(snippets, fmt.Sprintf("+ %s (%s)", s.Path, s.Language))
+	}
+	for _, s := range d.RemovedSnippetFiles {
+		snippets = append(snippets, fmt.Sprintf("- %s (%s)", s.Path, s.Language))
+	}
+	for _, s := range d.ChangedSnippets {
+		snippets = append(snippets, fmt.Sprintf("~ %s", s))
+	}
+	section("Snippets", snippets)
+
+	section("Issues", d.Issues)
+
+	if d.empty() {
+		sb.WriteString("No contribution changes\n")
+	}
+	return sb.String()
+}
+
+func (ckb *changedKeybinding) String() string {
+	var olds, news []string
+	for _, kb := range ckb.Old {
+		olds = append(olds, keybindingActionString(kb))
+	}
+	for _, kb := range ckb.New {
+		news = append(news, keybindingActionString(kb))
+	}
+	s := fmt.Sprintf("`%s`", ckb.Key)
+	if ckb.When != "" {
+		s += fmt.Sprintf(" when `%s`", ckb.When)
+	}
+	return fmt.Sprintf("%s: %s → %s", s, strings.Join(olds, ", "), strings.Join(news, ", "))
+}
+
+func commandsByID(p *Package) map[string]*Command {
+	m := map[string]*Command{}
+	for _, c := range p.Contributes.Commands {
+		m[c.Command] = c
+	}
+	return m
+}
+
+// keybindingsByKeyWhen groups the package's keybindings by key and when clause.
+func keybindingsByKeyWhen(p *Package) map[string][]*Keybinding {
+	m := map[string][]*Keybinding{}
+	for _, kb := range p.Contributes.Keybindings {
+		// Normalize the when clause so that formatting changes (e.g. added
+		// parentheses) aren't reported as a removed and added keybinding.
+		id := fmt.Sprintf("%s\x00%s", kb.Key, normalizeWhenClause(kb.When))
+		m[id] = append(m[id], kb)
+	}
+	return m
+}
+
+// keybindingActions returns the sorted command and args of each keybinding.
+func keybindingActions(kbs []*Keybinding) []string {
+	var r []string
+	for _, kb := range kbs {
+		r = append(r, keybindingActionString(kb))
+	}
+	sort.Strings(r)
+	return r
+}
+
+func keybindingActionString(kb *Keybinding) string {
+	s := "(no command)"
+	if kb.Command != "" {
+		s = fmt.Sprintf("`%s`", kb.Command)
+	}
+	if len(kb.Args) > 0 {
+		s += fmt.Sprintf(" with args `%s`", compactJson(kb.Args))
+	}
+	return s
+}
+
+func settingsByName(p *Package) map[string]map[string]interface{} {
+	m := map[string]map[string]interface{}{}
+	for _, c := range p.Contributes.Configuration {
+		for name, schema := range c.Properties {
+			m[name] = schema
+		}
+	}
+	return m
+}
+
+func snippetsByPath(p *Package) map[string]*Snippet {
+	m := map[string]*Snippet{}
+	for _, s := range p.Contributes.Snippets {
+		m[s.Path] = s
+	}
+	return m
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := maps.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
+
+// keybindingString returns a markdown description of the keybinding.
+func keybindingString(kb *Keybinding) string {
+	s := fmt.Sprintf("`%s` → %s", kb.Key, keybindingActionString(kb))
+	if kb.When != "" {
+		s += fmt.Sprintf(" when `%s`", kb.When)
+	}
+	return s
+}
+
+// compactJson returns the single-line json representation of the value
+// (without escaping html characters; see marshalJson).
+func compactJson(v interface{}) string {
+	var sb strings.Builder
+	encoder := json.NewEncoder(&sb)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSuggestedBump(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		diff *contributionDiff
+		want int
+	}{
+		{
+			name: "no changes",
+			diff: &contributionDiff{},
+			want: patchVersion,
+		},
+		{
+			name: "retitled command",
+			diff: &contributionDiff{RetitledCommands: []*retitledCommand{{"groog.a", "A", "B"}}},
+			want: patchVersion,
+		},
+		{
+			name: "changed setting default",
+			diff: &contributionDiff{ChangedSettings: []*changedSetting{{"groog.a", []string{"default"}}}},
+			want: patchVersion,
+		},
+		{
+			name: "added command",
+			diff: &contributionDiff{AddedCommands: []*Command{{"groog.a", "A"}}},
+			want: minorVersion,
+		},
+		{
+			name: "added setting",
+			diff: &contributionDiff{AddedSettings: []string{"groog.a"}},
+			want: minorVersion,
+		},
+		{
+			name: "added snippet file",
+			diff: &contributionDiff{AddedSnippetFiles: []*Snippet{{Path: "snippets/go.json", Language: "go"}}},
+			want: minorVersion,
+		},
+		{
+			name: "changed setting type",
+			diff: &contributionDiff{ChangedSettings: []*changedSetting{{"groog.a", []string{"default", "type"}}}},
+			want: majorVersion,
+		},
+		{
+			name: "changed keybinding",
+			diff: &contributionDiff{ChangedKeybindings: []*changedKeybinding{{Key: "ctrl+a"}}},
+			want: majorVersion,
+		},
+		{
+			name: "removed keybinding (with additions)",
+			diff: &contributionDiff{
+				AddedCommands:      []*Command{{"groog.a", "A"}},
+				RemovedKeybindings: []*Keybinding{{Key: "ctrl+a", Command: "groog.b"}},
+			},
+			want: majorVersion,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.diff.suggestedBump(); got != test.want {
+				t.Errorf("suggestedBump() returned %s; want %s", versionSectionNames[got], versionSectionNames[test.want])
+			}
+		})
+	}
+}
+
+func testPackage(c *Contribution) *Package {
+	return &Package{Contributes: c}
+}
+
+func testSnippetLoader(files map[string]string) snippetLoader {
+	return func(path string) ([]byte, error) {
+		s, ok := files[path]
+		if !ok {
+			return nil, fmt.Errorf("file does not exist")
+		}
+		return []byte(s), nil
+	}
+}
+
+func TestDiffContributions(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		old         *Contribution
+		new         *Contribution
+		oldSnippets map[string]string
+		newSnippets map[string]string
+		want        *contributionDiff
+	}{
+		{
+			name: "no changes",
+			old: &Contribution{
+				Commands:    []*Command{{"groog.a", "A"}},
+				Keybindings: []*Keybinding{{Key: "ctrl+a", Command: "groog.a", When: "editorTextFocus"}},
+			},
+			new: &Contribution{
+				Commands:    []*Command{{"groog.a", "A"}},
+				Keybindings: []*Keybinding{{Key: "ctrl+a", Command: "groog.a", When: "editorTextFocus"}},
+			},
+			want: &contributionDiff{},
+		},
+		{
+			name: "commands",
+			old: &Contribution{
+				Commands: []*Command{{"groog.a", "A"}, {"groog.b", "B"}},
+			},
+			new: &Contribution{
+				Commands: []*Command{{"groog.a", "Aa"}, {"groog.c", "C"}},
+			},
+			want: &contributionDiff{
+				AddedCommands:    []*Command{{"groog.c", "C"}},
+				RemovedCommands:  []*Command{{"groog.b", "B"}},
+				RetitledCommands: []*retitledCommand{{"groog.a", "A", "Aa"}},
+			},
+		},
+		{
+			name: "keybindings",
+			old: &Contribution{
+				Keybindings: []*Keybinding{
+					{Key: "ctrl+a", Command: "groog.a"},
+					{Key: "ctrl+b", Command: "groog.b", When: "editorTextFocus"},
+					{Key: "ctrl+c", Command: "groog.c", Args: map[string]interface{}{"n": 1}},
+				},
+			},
+			new: &Contribution{
+				Keybindings: []*Keybinding{
+					{Key: "ctrl+a", Command: "groog.a"},
+					{Key: "ctrl+b", Command: "groog.b", When: "!editorTextFocus"},
+					{Key: "ctrl+c", Command: "groog.c", Args: map[string]interface{}{"n": 2}},
+				},
+			},
+			want: &contributionDiff{
+				AddedKeybindings:   []*Keybinding{{Key: "ctrl+b", Command: "groog.b", When: "!editorTextFocus"}},
+				RemovedKeybindings: []*Keybinding{{Key: "ctrl+b", Command: "groog.b", When: "editorTextFocus"}},
+				ChangedKeybindings: []*changedKeybinding{{
+					Key: "ctrl+c",
+					Old: []*Keybinding{{Key: "ctrl+c", Command: "groog.c", Args: map[string]interface{}{"n": 1}}},
+					New: []*Keybinding{{Key: "ctrl+c", Command: "groog.c", Args: map[string]interface{}{"n": 2}}},
+				}},
+			},
+		},
+		{
+			name: "keybinding when clause formatting",
+			old: &Contribution{
+				Keybindings: []*Keybinding{
+					{Key: "ctrl+a", Command: "groog.a", When: "a && b || c"},
+					{Key: "ctrl+b", Command: "groog.b", When: "(a && b) && !c"},
+				},
+			},
+			new: &Contribution{
+				Keybindings: []*Keybinding{
+					{Key: "ctrl+a", Command: "groog.a", When: "(a && b) || c"},
+					{Key: "ctrl+b", Command: "groog.b2", When: "a && (b && !c)"},
+				},
+			},
+			want: &contributionDiff{
+				ChangedKeybindings: []*changedKeybinding{{
+					Key:  "ctrl+b",
+					When: "a && (b && !c)",
+					Old:  []*Keybinding{{Key: "ctrl+b", Command: "groog.b", When: "(a && b) && !c"}},
+					New:  []*Keybinding{{Key: "ctrl+b", Command: "groog.b2", When: "a && (b && !c)"}},
+				}},
+			},
+		},
+		{
+			name: "settings",
+			old: &Contribution{
+				Configuration: []*Configuration{{
+					Properties: map[string]map[string]interface{}{
+						"groog.a": {"type": "string", "default": "a"},
+						"groog.b": {"type": "boolean"},
+					},
+				}},
+			},
+			new: &Contribution{
+				Configuration: []*Configuration{
+					{Properties: map[string]map[string]interface{}{
+						"groog.a": {"type": "number", "default": 1},
+					}},
+					{Properties: map[string]map[string]interface{}{
+						"groog.c": {"type": "boolean"},
+					}},
+				},
+			},
+			want: &contributionDiff{
+				AddedSettings:   []string{"groog.c"},
+				RemovedSettings: []string{"groog.b"},
+				ChangedSettings: []*changedSetting{{"groog.a", []string{"default", "type"}}},
+			},
+		},
+		{
+			name: "snippets",
+			old: &Contribution{
+				Snippets: []*Snippet{
+					{"snippets/go.json", "go"},
+					{"snippets/java.json", "java"},
+					{"snippets/old.json", "python"},
+				},
+			},
+			new: &Contribution{
+				Snippets: []*Snippet{
+					{"snippets/go.json", "go"},
+					{"snippets/java.json", "kotlin"},
+					{"snippets/new.json", "python"},
+				},
+			},
+			oldSnippets: map[string]string{
+				"snippets/go.json":   `{"a": {"prefix": "a", "body": "a"}, "b": {"prefix": "b", "body": ["b"]}, "c": {"prefix": "c", "body": "c"}}`,
+				"snippets/java.json": `{}`,
+			},
+			newSnippets: map[string]string{
+				"snippets/go.json":   `{"a": {"prefix": ["a"], "body": ["a"]}, "b": {"prefix": "b", "body": ["bb"]}, "d": {"prefix": "d", "body": "d"}}`,
+				"snippets/java.json": `{`,
+			},
+			want: &contributionDiff{
+				AddedSnippetFiles:   []*Snippet{{"snippets/new.json", "python"}},
+				RemovedSnippetFiles: []*Snippet{{"snippets/old.json", "python"}},
+				ChangedSnippets: []string{
+					`snippets/go.json: changed "b"`,
+					`snippets/go.json: added "d"`,
+					`snippets/go.json: removed "c"`,
+					`snippets/java.json: language changed from "java" to "kotlin"`,
+				},
+				Issues: []string{
+					"failed to parse snippet file snippets/java.json: unexpected end of JSON input",
+				},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := diffContributions(testPackage(test.old), testPackage(test.new), testSnippetLoader(test.oldSnippets), testSnippetLoader(test.newSnippets))
+			if compactJson(got) != compactJson(test.want) {
+				t.Errorf("diffContributions() returned\n%s\nwant\n%s", compactJson(got), compactJson(test.want))
+			}
+		})
+	}
+}
+
+func TestContributionDiffReport(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		diff *contributionDiff
+		want []string
+	}{
+		{
+			name: "no changes",
+			diff: &contributionDiff{},
+			want: []string{"No contribution changes"},
+		},
+		{
+			name: "all changes",
+			diff: &contributionDiff{
+				AddedCommands:      []*Command{{"groog.c", "C"}},
+				RemovedCommands:    []*Command{{"groog.b", "B"}},
+				RetitledCommands:   []*retitledCommand{{"groog.a", "A", "Aa"}},
+				AddedKeybindings:   []*Keybinding{{Key: "ctrl+b", Command: "groog.b", When: "!editorTextFocus"}},
+				RemovedKeybindings: []*Keybinding{{Key: "ctrl+d"}},
+				ChangedKeybindings: []*changedKeybinding{{
+					Key:  "ctrl+c",
+					When: "a",
+					Old:  []*Keybinding{{Key: "ctrl+c", Command: "groog.c", Args: map[string]interface{}{"n": 1}}},
+					New:  []*Keybinding{{Key: "ctrl+c", Command: "groog.c"}, {Key: "ctrl+c", Command: "groog.d"}},
+				}},
+				AddedSettings:       []string{"groog.c"},
+				RemovedSettings:     []string{"groog.b"},
+				ChangedSettings:     []*changedSetting{{"groog.a", []string{"default", "type"}}},
+				AddedSnippetFiles:   []*Snippet{{"snippets/new.json", "python"}},
+				RemovedSnippetFiles: []*Snippet{{"snippets/old.json", "go"}},
+				ChangedSnippets:     []string{`snippets/go.json: changed "b"`},
+				Issues:              []string{"failed to load snippet file"},
+			},
+			want: []string{
+				"Commands:",
+				"  + groog.c (C)",
+				"  - groog.b (B)",
+				`  ~ groog.a: "A" → "Aa"`,
+				"Keybindings:",
+				"  + `ctrl+b` → `groog.b` when `!editorTextFocus`",
+				"  - `ctrl+d` → (no command)",
+				"  ~ `ctrl+c` when `a`: `groog.c` with args `{\"n\":1}` → `groog.c`, `groog.d`",
+				"Settings:",
+				"  + groog.c",
+				"  - groog.b",
+				"  ~ groog.a (default, type)",
+				"Snippets:",
+				"  + snippets/new.json (python)",
+				"  - snippets/old.json (go)",
+				`  ~ snippets/go.json: changed "b"`,
+				"Issues:",
+				"  failed to load snippet file",
+			},
+		},
+		{
+			name: "single section",
+			diff: &contributionDiff{AddedSettings: []string{"groog.c"}},
+			want: []string{
+				"Settings:",
+				"  + groog.c",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got, want := test.diff.report(), strings.Join(test.want, "\n")+"\n"; got != want {
+				t.Errorf("report() returned:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var (
+	// packageOwnedSections are the package.json sections (as dot-separated
+	// paths) that are generated from the go sources. Generating package.json
+	// replaces these sections and leaves everything else (e.g. manually added
+	// `galleryBanner`, `keywords`, or `contributes.menus` sections) as is.
+	packageOwnedSections = []string{
+		"name",
+		"displayName",
+		"description",
+		"version",
+		"publisher",
+		"main",
+		"engines",
+		"repository",
+		"categories",
+		"icon",
+		"scripts",
+		"dependencies",
+		"devDependencies",
+		"activationEvents",
+		"contributes.commands",
+		"contributes.keybindings",
+		"contributes.configuration",
+		"contributes.configurationDefaults",
+		"contributes.snippets",
+		"extensionKind",
+		"extensionPack",
+	}
+)
+
+// mergePackageJSON overlays the owned sections of the generated package.json
+// onto the existing package.json contents. Unowned keys keep their position,
+// and owned keys that aren't in the existing file are placed after the
+// preceding generated key.
+func mergePackageJSON(existing, generated []byte) ([]byte, error) {
+	e := &jsonObject{values: map[string]json.RawMessage{}}
+	if len(bytes.TrimSpace(existing)) > 0 {
+		var err error
+		if e, err = parseJSONObject(existing); err != nil {
+			return nil, fmt.Errorf("failed to parse existing package.json: %v", err)
+		}
+	}
+
+	g, err := parseJSONObject(generated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated package.json: %v", err)
+	}
+
+	merged, err := mergeJSONObjects(e, g, packageOwnedSections, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, merged.marshal(), "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to indent json: %v", err)
+	}
+	indented.WriteString("\n")
+	return indented.Bytes(), nil
+}
+
+func mergeJSONObjects(existing, generated *jsonObject, owned []string, path string) (*jsonObject, error) {
+	fullyOwned := map[string]bool{}
+	nestedOwned := map[string][]string{}
+	for _, o := range owned {
+		if k, rest, ok := strings.Cut(o, "."); ok {
+			nestedOwned[k] = append(nestedOwned[k], rest)
+		} else {
+			fullyOwned[k] = true
+		}
+	}
+
+	for _, k := range generated.keys {
+		if !fullyOwned[k] && nestedOwned[k] == nil {
+			return nil, fmt.Errorf("generated section %q is not in the owned sections allowlist", path+k)
+		}
+	}
+
+	// mergeNested merges the nested object at key k (treating missing values
+	// as empty objects).
+	mergeNested := func(k string) (json.RawMessage, error) {
+		eo := &jsonObject{values: map[string]json.RawMessage{}}
+		if ev, ok := existing.values[k]; ok {
+			var err error
+			if eo, err = parseJSONObject(ev); err != nil {
+				return nil, fmt.Errorf("existing section %q is not an object: %v", path+k, err)
+			}
+		}
+		gv, ok := generated.values[k]
+		if !ok {
+			gv = json.RawMessage("{}")
+		}
+		gvo, err := parseJSONObject(gv)
+		if err != nil {
+			return nil, fmt.Errorf("generated section %q is not an object: %v", path+k, err)
+		}
+		m, err := mergeJSONObjects(eo, gvo, nestedOwned[k], path+k+".")
+		if err != nil {
+			return nil, err
+		}
+		return m.marshal(), nil
+	}
+
+	r := &jsonObject{values: map[string]json.RawMessage{}}
+	for _, k := range existing.keys {
+		switch {
+		case fullyOwned[k]:
+			// Owned sections that are no longer generated are removed.
+			if v, ok := generated.values[k]; ok {
+				r.set(k, v)
+			}
+		case nestedOwned[k] != nil:
+			v, err := mergeNested(k)
+			if err != nil {
+				return nil, err
+			}
+			r.set(k, v)
+		default:
+			r.set(k, existing.values[k])
+		}
+	}
+
+	for i, k := range generated.keys {
+		if _, ok := r.values[k]; ok {
+			continue
+		}
+
+		v := generated.values[k]
+		if nestedOwned[k] != nil {
+			var err error
+			if v, err = mergeNested(k); err != nil {
+				return nil, err
+			}
+		}
+
+		// Insert after the closest preceding generated key (or at the start).
+		at := 0
+		for j := i - 1; j >= 0; j-- {
+			if idx := r.index(generated.keys[j]); idx >= 0 {
+				at = idx + 1
+				break
+			}
+		}
+		r.insert(at, k, v)
+	}
+	return r, nil
+}
+
+// jsonObject is a json object that preserves the order of its keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func parseJSONObject(b []byte) (*jsonObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	t, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if t != json.Delim('{') {
+		return nil, fmt.Errorf("expected json object; got %v", t)
+	}
+
+	o := &jsonObject{values: map[string]json.RawMessage{}}
+	for decoder.More() {
+		t, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		k, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected object key; got %v", t)
+		}
+
+		var v json.RawMessage
+		if err := decoder.Decode(&v); err != nil {
+			return nil, err
+		}
+		o.set(k, v)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *jsonObject) set(k string, v json.RawMessage) {
+	if _, ok := o.values[k]; !ok {
+		o.keys = append(o.keys, k)
+	}
+	o.values[k] = v
+}
+
+func (o *jsonObject) insert(at int, k string, v json.RawMessage) {
+	o.keys = append(o.keys[:at], append([]string{k}, o.keys[at:]...)...)
+	o.values[k] = v
+}
+
+func (o *jsonObject) index(k string) int {
+	for i, key := range o.keys {
+		if key == k {
+			return i
+		}
+	}
+	return -1
+}
+
+// marshal returns the compact json representation of the object.
+func (o *jsonObject) marshal() json.RawMessage {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, k := range o.keys {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(compactJson(k))
+		b.WriteString(":")
+		if err := json.Compact(&b, o.values[k]); err != nil {
+			b.Write(o.values[k])
+		}
+	}
+	b.WriteString("}")
+	return b.Bytes()
+}
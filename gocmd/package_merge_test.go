@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMergePackageJSON(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		existing  string
+		generated string
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "no existing file",
+			generated: `{"name": "groog", "version": "1.0.0"}`,
+			want:      `{"name":"groog","version":"1.0.0"}`,
+		},
+		{
+			name:      "replaces owned sections",
+			existing:  `{"name": "old", "version": "0.0.1"}`,
+			generated: `{"name": "groog", "version": "1.0.0"}`,
+			want:      `{"name":"groog","version":"1.0.0"}`,
+		},
+		{
+			name:      "keeps unowned keys in place",
+			existing:  `{"keywords": ["a"], "name": "old", "galleryBanner": {"color": "#000"}, "version": "0.0.1", "license": "MIT"}`,
+			generated: `{"name": "groog", "version": "1.0.0"}`,
+			want:      `{"keywords":["a"],"name":"groog","galleryBanner":{"color":"#000"},"version":"1.0.0","license":"MIT"}`,
+		},
+		{
+			name:      "inserts new keys after the nearest earlier generated key",
+			existing:  `{"license": "MIT", "name": "old", "keywords": ["a"], "main": "./out/extension.js"}`,
+			generated: `{"name": "groog", "displayName": "Groog", "description": "desc", "main": "./out/extension.js", "engines": {"vscode": "^1.96.0"}}`,
+			want:      `{"license":"MIT","name":"groog","displayName":"Groog","description":"desc","keywords":["a"],"main":"./out/extension.js","engines":{"vscode":"^1.96.0"}}`,
+		},
+		{
+			name:      "inserts new keys at the start if no earlier generated key exists",
+			existing:  `{"license": "MIT", "version": "0.0.1"}`,
+			generated: `{"name": "groog", "version": "1.0.0"}`,
+			want:      `{"name":"groog","license":"MIT","version":"1.0.0"}`,
+		},
+		{
+			name:      "removes owned sections that are no longer generated",
+			existing:  `{"name": "groog", "icon": "icon.png", "extensionPack": ["a.b"], "license": "MIT"}`,
+			generated: `{"name": "groog"}`,
+			want:      `{"name":"groog","license":"MIT"}`,
+		},
+		{
+			name:      "merges nested contributes sections",
+			existing:  `{"name": "groog", "contributes": {"menus": {"editor/context": []}, "commands": [{"command": "old"}], "keybindings": [], "snippets": []}}`,
+			generated: `{"name": "groog", "contributes": {"commands": [{"command": "new"}], "configuration": [], "keybindings": [{"key": "ctrl+a"}]}}`,
+			want:      `{"name":"groog","contributes":{"menus":{"editor/context":[]},"commands":[{"command":"new"}],"configuration":[],"keybindings":[{"key":"ctrl+a"}]}}`,
+		},
+		{
+			name:      "adds contributes section",
+			existing:  `{"name": "groog", "license": "MIT"}`,
+			generated: `{"name": "groog", "contributes": {"commands": []}}`,
+			want:      `{"name":"groog","contributes":{"commands":[]},"license":"MIT"}`,
+		},
+		{
+			name:      "keeps unowned contributes keys when nothing is generated",
+			existing:  `{"name": "groog", "contributes": {"menus": {}, "commands": []}}`,
+			generated: `{"name": "groog"}`,
+			want:      `{"name":"groog","contributes":{"menus":{}}}`,
+		},
+		{
+			name:      "generated section is not owned",
+			generated: `{"name": "groog", "keywords": []}`,
+			wantErr:   `generated section "keywords" is not in the owned sections allowlist`,
+		},
+		{
+			name:      "generated nested section is not owned",
+			generated: `{"contributes": {"menus": {}}}`,
+			wantErr:   `generated section "contributes.menus" is not in the owned sections allowlist`,
+		},
+		{
+			name:      "generated nested section is not an object",
+			generated: `{"contributes": []}`,
+			wantErr:   `generated section "contributes" is not an object: expected json object; got [`,
+		},
+		{
+			name:      "existing nested section is not an object",
+			existing:  `{"contributes": "oops"}`,
+			generated: `{"contributes": {"commands": []}}`,
+			wantErr:   `existing section "contributes" is not an object: expected json object; got oops`,
+		},
+		{
+			name:      "invalid existing file",
+			existing:  `[]`,
+			generated: `{}`,
+			wantErr:   "failed to parse existing package.json: expected json object; got [",
+		},
+		{
+			name:      "invalid generated file",
+			generated: `{"name": }`,
+			wantErr:   "failed to parse generated package.json: invalid character '}' looking for beginning of value",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := mergePackageJSON([]byte(test.existing), []byte(test.generated))
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("mergePackageJSON() returned error %v; want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergePackageJSON() returned error: %v", err)
+			}
+			if !strings.HasSuffix(string(got), "}\n") {
+				t.Errorf("mergePackageJSON() returned %q; want a trailing newline", got)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, got); err != nil {
+				t.Fatalf("mergePackageJSON() returned invalid json: %v", err)
+			}
+			if compact.String() != test.want {
+				t.Errorf("mergePackageJSON() returned %s; want %s", compact.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestMergeJSONObjects(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		existing  string
+		generated string
+		owned     []string
+		want      string
+	}{
+		{
+			name:      "deeply nested ownership",
+			existing:  `{"a": {"b": {"c": 1, "d": 2}, "e": 3}}`,
+			generated: `{"a": {"b": {"c": 4}}}`,
+			owned:     []string{"a.b.c"},
+			want:      `{"a":{"b":{"c":4,"d":2},"e":3}}`,
+		},
+		{
+			name:      "removed deeply nested section",
+			existing:  `{"a": {"b": {"c": 1, "d": 2}}}`,
+			generated: `{}`,
+			owned:     []string{"a.b.c", "a.b.d"},
+			want:      `{"a":{"b":{}}}`,
+		},
+		{
+			name:      "new nested section without an earlier generated key",
+			existing:  `{"a": {"x": 1, "b": 2}}`,
+			generated: `{"a": {"c": 3, "b": 4}}`,
+			owned:     []string{"a.b", "a.c"},
+			want:      `{"a":{"c":3,"x":1,"b":4}}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := parseJSONObject([]byte(test.existing))
+			if err != nil {
+				t.Fatalf("parseJSONObject() returned error: %v", err)
+			}
+			g, err := parseJSONObject([]byte(test.generated))
+			if err != nil {
+				t.Fatalf("parseJSONObject() returned error: %v", err)
+			}
+			got, err := mergeJSONObjects(e, g, test.owned, "")
+			if err != nil {
+				t.Fatalf("mergeJSONObjects() returned error: %v", err)
+			}
+			if string(got.marshal()) != test.want {
+				t.Errorf("mergeJSONObjects() returned %s; want %s", got.marshal(), test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGroogPackage(t *testing.T) {
+	p, err := groogPackage("1.2.3", defaultKeyboardLayout)
+	if err != nil {
+		t.Fatalf("groogPackage() returned error: %v", err)
+	}
+
+	for _, test := range []struct {
+		section string
+		value   interface{}
+	}{
+		{"commands", p.Contributes.Commands},
+		{"keybindings", p.Contributes.Keybindings},
+		{"configuration", p.Contributes.Configuration},
+		{"configurationDefaults", p.Contributes.ConfigurationDefaults},
+		{"snippets", p.Contributes.Snippets},
+		{"extensionPack", p.ExtensionPack},
+	} {
+		t.Run(test.section, func(t *testing.T) {
+			b, err := marshalJson(test.value)
+			if err != nil {
+				t.Fatalf("marshalJson() returned error: %v", err)
+			}
+			checkGolden(t, fmt.Sprintf("package/%s.golden.json", test.section), string(b))
+		})
+	}
+}
+
+func TestMarshalJson(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{
+			name:  "string",
+			value: "abc",
+			want:  "\"abc\"\n",
+		},
+		{
+			name:  "does not escape html characters",
+			value: "a && b <c>",
+			want:  "\"a && b <c>\"\n",
+		},
+		{
+			name: "indents with two spaces",
+			value: map[string]interface{}{
+				"b": []int{1, 2},
+				"a": map[string]bool{"c": true},
+			},
+			want: `{
+  "a": {
+    "c": true
+  },
+  "b": [
+    1,
+    2
+  ]
+}
+`,
+		},
+		{
+			name: "omits empty keybinding fields",
+			value: &Keybinding{
+				Key:     "ctrl+x ctrl+s",
+				Command: "groog.save",
+			},
+			want: `{
+  "key": "ctrl+x ctrl+s",
+  "command": "groog.save"
+}
+`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := marshalJson(test.value)
+			if err != nil {
+				t.Fatalf("marshalJson() returned error: %v", err)
+			}
+			if string(got) != test.want {
+				t.Errorf("marshalJson() returned:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaEvaluate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		schema *JSONSchema
+		want   string
+	}{
+		{
+			name:   "string",
+			schema: NewJSONString(),
+			want:   `{"type":"string"}`,
+		},
+		{
+			name:   "integer with options",
+			schema: NewJSONInteger(JSONDefault(5), JSONMinimum(0), JSONDescription("A number")),
+			want:   `{"default":5,"description":"A number","minimum":0,"type":"integer"}`,
+		},
+		{
+			name:   "later options override earlier ones",
+			schema: NewJSONBool(JSONDefault(true), JSONDefault(false)),
+			want:   `{"default":false,"type":"boolean"}`,
+		},
+		{
+			name:   "array",
+			schema: NewJSONArray(NewJSONString(JSONMarkdownDescription("`item`")), JSONScope("resource")),
+			want:   `{"items":{"markdownDescription":"` + "`item`" + `","type":"string"},"scope":"resource","type":"array"}`,
+		},
+		{
+			name: "object",
+			schema: NewJSONObject(map[string]*JSONSchema{
+				"name":  NewJSONString(),
+				"count": NewJSONInteger(JSONDefault(1)),
+			}, JSONOrder(2)),
+			want: `{"order":2,"properties":{"count":{"default":1,"type":"integer"},"name":{"type":"string"}},"type":"object"}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := compactJson(test.schema.evaluate()); got != test.want {
+				t.Errorf("evaluate() returned %s; want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaValidate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		schema *JSONSchema
+		value  interface{}
+		want   []string
+	}{
+		{
+			name:   "valid integer",
+			schema: NewJSONInteger(JSONMinimum(1)),
+			value:  2.0,
+		},
+		{
+			name:   "integer below the minimum",
+			schema: NewJSONInteger(JSONMinimum(1)),
+			value:  0.0,
+			want:   []string{"p: value 0 is less than the minimum (1)"},
+		},
+		{
+			name:   "float minimum",
+			schema: NewJSONSchema(&JSONSchemaSimpleType{"number"}, JSONSchemaOption{"minimum": 0.5}),
+			value:  0.25,
+			want:   []string{"p: value 0.25 is less than the minimum (0.5)"},
+		},
+		{
+			name:   "non-numeric minimum",
+			schema: NewJSONInteger(JSONSchemaOption{"minimum": "one"}),
+			value:  2.0,
+			want:   []string{"p: minimum (one) is not a number"},
+		},
+		{
+			name:   "enum value",
+			schema: NewJSONString(JSONSchemaOption{"enum": []string{"a", "b"}}),
+			value:  "b",
+		},
+		{
+			name:   "value not in enum",
+			schema: NewJSONString(JSONSchemaOption{"enum": []string{"a", "b"}}),
+			value:  "c",
+			want:   []string{"p: value c is not one of the allowed enum values [a b]"},
+		},
+		{
+			name:   "enum is not a list",
+			schema: NewJSONString(JSONSchemaOption{"enum": "a"}),
+			value:  "a",
+			want:   []string{"p: enum (a) is not a list"},
+		},
+		{
+			name:   "type mismatch",
+			schema: NewJSONString(),
+			value:  1.0,
+			want:   []string{typeMismatch("p", "string", 1.0)},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.schema.validate("p", test.value)
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("validate() returned %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConfigurationSectionOrder(t *testing.T) {
+	cs := &ConfigurationSection{
+		Title: "Section",
+		Properties: []*ConfigurationProperty{
+			{"unordered", NewJSONString()},
+			configProperty("second", 2, NewJSONString()),
+			configProperty("first", 1, NewJSONString()),
+		},
+	}
+
+	got := map[string]interface{}{}
+	for name, v := range cs.configuration(3).Properties {
+		got[name] = v["order"]
+	}
+	want := map[string]interface{}{
+		"first":     1,
+		"second":    2,
+		"unordered": 3,
+	}
+	if compactJson(got) != compactJson(want) {
+		t.Errorf("configuration() returned property orders %s; want %s", compactJson(got), compactJson(want))
+	}
+}
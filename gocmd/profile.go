@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// See the following link for details on VS Code profiles:
+// https://code.visualstudio.com/docs/editor/profiles#_share-profiles
+
+const (
+	profileName = "groog"
+)
+
+var (
+	// profilePlatforms maps platform names to VS Code's internal platform
+	// enum values (which determine the platform the keybindings are imported
+	// for).
+	profilePlatforms = map[string]int{
+		"web":     0,
+		"mac":     1,
+		"linux":   2,
+		"windows": 3,
+	}
+)
+
+// Profile is the contents of a `.code-profile` file. Each resource is itself a
+// json string.
+type Profile struct {
+	Name        string `json:"name"`
+	Settings    string `json:"settings"`
+	Keybindings string `json:"keybindings"`
+	Extensions  string `json:"extensions"`
+}
+
+// ProfileExtension is an extension installed by a profile.
+type ProfileExtension struct {
+	Identifier  *ProfileExtensionIdentifier `json:"identifier"`
+	DisplayName string                      `json:"displayName,omitempty"`
+}
+
+type ProfileExtensionIdentifier struct {
+	ID string `json:"id"`
+}
+
+func profileExtension(id, displayName string) *ProfileExtension {
+	return &ProfileExtension{&ProfileExtensionIdentifier{id}, displayName}
+}
+
+// groogProfile returns the profile containing groog (and its companion
+// extensions), the groog settings, and the keybindings for the keyboard
+// layout and platform.
+func groogProfile(p *Package, layout *KeyboardLayout, platform string) (*Profile, error) {
+	platformID, ok := profilePlatforms[strings.ToLower(platform)]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile platform %q; expected one of %v", platform, sortedKeys(profilePlatforms))
+	}
+
+	gs, err := groogSettings()
+	if err != nil {
+		return nil, err
+	}
+	sj, err := settingsJSON(gs)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := marshalJson(sj)
+	if err != nil {
+		return nil, err
+	}
+	settingsResource, err := marshalProfileResource(map[string]interface{}{
+		"settings": string(settings),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	kbs := kbDefsToBindings(layout)
+	keybindings, err := marshalJson(kbs)
+	if err != nil {
+		return nil, err
+	}
+	keybindingsResource, err := marshalProfileResource(map[string]interface{}{
+		"keybindings": string(keybindings),
+		"platform":    platformID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extensions := append([]*ProfileExtension{
+		profileExtension(fmt.Sprintf("%s.%s", p.Publisher, p.Name), p.DisplayName),
+	}, profileExtensions(kbs)...)
+	extensionsResource, err := marshalProfileResource(extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{
+		Name:        profileName,
+		Settings:    settingsResource,
+		Keybindings: keybindingsResource,
+		Extensions:  extensionsResource,
+	}, nil
+}
+
+// profileExtensions returns the companion extensions that are installed by the
+// profile: the extensions whose commands are run by the keybindings and the
+// extensions in groog's extensionPack.
+func profileExtensions(kbs []*Keybinding) []*ProfileExtension {
+	bound := extensionBindings(kbs)
+	var pes []*ProfileExtension
+	for _, ce := range companionExtensions {
+		if ce.Pack || len(bound[ce.ID]) > 0 {
+			pes = append(pes, profileExtension(ce.ID, ""))
+		}
+	}
+	return pes
+}
+
+// marshalProfileResource returns the compact json string for a profile
+// resource (without escaping html characters; see marshalJson).
+func marshalProfileResource(v interface{}) (string, error) {
+	var sb strings.Builder
+	encoder := json.NewEncoder(&sb)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return "", fmt.Errorf("failed to marshal profile resource: %v", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
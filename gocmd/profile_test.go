@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGroogProfile(t *testing.T) {
+	p, err := groogPackage("1.2.3", defaultKeyboardLayout)
+	if err != nil {
+		t.Fatalf("groogPackage() returned error: %v", err)
+	}
+
+	profile, err := groogProfile(p, defaultKeyboardLayout, "linux")
+	if err != nil {
+		t.Fatalf("groogProfile() returned error: %v", err)
+	}
+
+	var settings map[string]string
+	if err := json.Unmarshal([]byte(profile.Settings), &settings); err != nil {
+		t.Fatalf("failed to unmarshal settings resource: %v", err)
+	}
+	checkGolden(t, "profile/settings.golden.json", settings["settings"])
+
+	var extensions interface{}
+	if err := json.Unmarshal([]byte(profile.Extensions), &extensions); err != nil {
+		t.Fatalf("failed to unmarshal extensions resource: %v", err)
+	}
+	b, err := marshalJson(extensions)
+	if err != nil {
+		t.Fatalf("marshalJson() returned error: %v", err)
+	}
+	checkGolden(t, "profile/extensions.golden.json", string(b))
+}
+
+func TestGroogProfileUnknownPlatform(t *testing.T) {
+	p, err := groogPackage("1.2.3", defaultKeyboardLayout)
+	if err != nil {
+		t.Fatalf("groogPackage() returned error: %v", err)
+	}
+
+	want := `unknown profile platform "amiga"; expected one of [linux mac web windows]`
+	if _, err := groogProfile(p, defaultKeyboardLayout, "amiga"); err == nil || err.Error() != want {
+		t.Errorf("groogProfile() returned error %v; want %q", err, want)
+	}
+}
+
+func TestSettingsJSON(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		settings []*GroogSetting
+		want     string
+		wantErr  string
+	}{
+		{
+			name: "groups language settings",
+			settings: []*GroogSetting{
+				groogSetting("editor", "tabSize", 2),
+				languageSetting("go", "editor", "tabSize", 4),
+				languageSetting("go", "editor", "formatOnSave", true),
+			},
+			want: `{"[go]":{"editor.formatOnSave":true,"editor.tabSize":4},"editor.tabSize":2}`,
+		},
+		{
+			name: "adds word separators",
+			settings: []*GroogSetting{
+				wordSeparatorSetting("_-"),
+			},
+			want: `{"editor.wordSeparators":"` + "`" + `~!@#$%^&*()-=+[{]}\\|;:'\",.<>/?_"}`,
+		},
+		{
+			name: "duplicate setting",
+			settings: []*GroogSetting{
+				groogSetting("editor", "tabSize", 2),
+				groogSetting("editor", "tabSize", 4),
+			},
+			wantErr: `duplicate setting "editor.tabSize"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := settingsJSON(test.settings)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("settingsJSON() returned error %v; want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("settingsJSON() returned error: %v", err)
+			}
+			if compactJson(got) != test.want {
+				t.Errorf("settingsJSON() returned %s; want %s", compactJson(got), test.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedSettingsFile(t *testing.T) {
+	want, err := generatedSettings()
+	if err != nil {
+		t.Fatalf("generatedSettings() returned error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join("..", filepath.FromSlash(generatedSettingsFile)))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", generatedSettingsFile, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s is out of date; run `vs-package` to regenerate it:\n%s", generatedSettingsFile, unifiedDiff("a/"+generatedSettingsFile, "b/"+generatedSettingsFile, string(got), string(want)))
+	}
+
+	// src/settings.ts should only define settings that depend on the user's
+	// configuration or the workspace (everything else is generated).
+	ts, err := os.ReadFile(filepath.Join("..", "src", "settings.ts"))
+	if err != nil {
+		t.Fatalf("failed to read src/settings.ts: %v", err)
+	}
+	if !strings.Contains(string(ts), "require('./"+filepath.Base(generatedSettingsFile)+"')") {
+		t.Errorf("src/settings.ts doesn't import %s", generatedSettingsFile)
+	}
+	for _, m := range regexp.MustCompile(`new GroogSetting\("([^"]*)", "([^"]*)"`).FindAllStringSubmatch(string(ts), -1) {
+		if name := m[1] + "." + m[2]; name != "coverage-gutters.manualCoverageFilePaths" {
+			t.Errorf("src/settings.ts defines setting %q; add it to fixedSettings in settings.go instead", name)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A removal keybinding (a command prefixed with `-`) only removes the default
+// keybindings with the same command, the same key (if the removal has one),
+// and the same when clause (if the removal has one). See the following link
+// for more details:
+// https://code.visualstudio.com/docs/getstarted/keybindings#_removing-a-specific-key-binding-rule
+
+// unmatchedRemoval is a removal keybinding that doesn't remove any default
+// keybinding.
+type unmatchedRemoval struct {
+	removal *Keybinding
+	// defaults are the default keybindings for the removed command.
+	defaults []*Keybinding
+}
+
+func (ur *unmatchedRemoval) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s matches no default keybinding", keybindingString(ur.removal))
+
+	cmd := strings.TrimPrefix(ur.removal.Command, "-")
+	if len(ur.defaults) == 0 {
+		if commandExtension(cmd) != nil {
+			fmt.Fprintf(&sb, "; %s has no default keybindings (is its extension installed?)\n", cmd)
+		} else {
+			fmt.Fprintf(&sb, "; %s has no default keybindings\n", cmd)
+		}
+		return sb.String()
+	}
+
+	sb.WriteString("; use one of the following to remove the default keybinding:\n")
+	for _, d := range ur.defaults {
+		fmt.Fprintf(&sb, "  %s\n", compactJson(&Keybinding{
+			Key:     d.Key,
+			Command: ur.removal.Command,
+			When:    d.When,
+		}))
+	}
+	return sb.String()
+}
+
+// unmatchedRemovals returns the removal keybindings that don't match any of
+// the default keybindings.
+func unmatchedRemovals(kbs, defaults []*Keybinding) []*unmatchedRemoval {
+	byCommand := map[string][]*Keybinding{}
+	for _, d := range defaults {
+		byCommand[d.Command] = append(byCommand[d.Command], d)
+	}
+
+	var urs []*unmatchedRemoval
+	for _, kb := range kbs {
+		if !strings.HasPrefix(kb.Command, "-") {
+			continue
+		}
+
+		cmdDefaults := byCommand[strings.TrimPrefix(kb.Command, "-")]
+		if !removesAny(kb, cmdDefaults) {
+			urs = append(urs, &unmatchedRemoval{kb, cmdDefaults})
+		}
+	}
+	return urs
+}
+
+// removesAny returns whether the removal keybinding matches any of the
+// default keybindings.
+func removesAny(removal *Keybinding, defaults []*Keybinding) bool {
+	for _, d := range defaults {
+		if removes(removal, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// removes returns whether the removal keybinding removes the keybinding. A
+// removal without a key removes all of the command's keybindings, and a
+// removal without a when clause removes the keybindings for every when clause.
+func removes(removal, kb *Keybinding) bool {
+	if !strings.HasPrefix(removal.Command, "-") || strings.TrimPrefix(removal.Command, "-") != kb.Command {
+		return false
+	}
+	if removal.Key != "" && normalizeKey(removal.Key) != normalizeKey(kb.Key) {
+		return false
+	}
+	return removal.When == "" || normalizeWhen(removal.When) == normalizeWhen(kb.When)
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(key), " "))
+}
+
+func normalizeWhen(when string) string {
+	return strings.Join(strings.Fields(when), "")
+}
+
+// removalReport returns a report of the removal keybindings that don't match
+// any default keybinding (and the number of unmatched removals).
+func removalReport(kbs, defaults []*Keybinding) (string, int) {
+	urs := unmatchedRemovals(kbs, defaults)
+
+	var sb strings.Builder
+	for _, ur := range urs {
+		sb.WriteString(ur.String())
+	}
+	return sb.String(), len(urs)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRemoves(t *testing.T) {
+	kb := &Keybinding{Key: "ctrl+k ctrl+c", Command: "editor.action.addCommentLine", When: "editorTextFocus && !editorReadonly"}
+
+	for _, test := range []struct {
+		name    string
+		removal *Keybinding
+		want    bool
+	}{
+		{
+			name:    "same key and when clause",
+			removal: &Keybinding{Key: "ctrl+k ctrl+c", Command: "-editor.action.addCommentLine", When: "editorTextFocus && !editorReadonly"},
+			want:    true,
+		},
+		{
+			name:    "no when clause",
+			removal: &Keybinding{Key: "ctrl+k ctrl+c", Command: "-editor.action.addCommentLine"},
+			want:    true,
+		},
+		{
+			name:    "no key",
+			removal: &Keybinding{Command: "-editor.action.addCommentLine"},
+			want:    true,
+		},
+		{
+			name:    "no key with the same when clause",
+			removal: &Keybinding{Command: "-editor.action.addCommentLine", When: "editorTextFocus && !editorReadonly"},
+			want:    true,
+		},
+		{
+			name:    "no key with a different when clause",
+			removal: &Keybinding{Command: "-editor.action.addCommentLine", When: "editorTextFocus"},
+		},
+		{
+			name:    "key and when clause are normalized",
+			removal: &Keybinding{Key: "Ctrl+K  ctrl+c", Command: "-editor.action.addCommentLine", When: "editorTextFocus&&!editorReadonly"},
+			want:    true,
+		},
+		{
+			name:    "different key",
+			removal: &Keybinding{Key: "ctrl+k ctrl+u", Command: "-editor.action.addCommentLine"},
+		},
+		{
+			name:    "different when clause",
+			removal: &Keybinding{Key: "ctrl+k ctrl+c", Command: "-editor.action.addCommentLine", When: "editorTextFocus"},
+		},
+		{
+			name:    "different command",
+			removal: &Keybinding{Key: "ctrl+k ctrl+c", Command: "-editor.action.removeCommentLine"},
+		},
+		{
+			name:    "not a removal",
+			removal: &Keybinding{Key: "ctrl+k ctrl+c", Command: "editor.action.addCommentLine"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := removes(test.removal, kb); got != test.want {
+				t.Errorf("removes(%s, %s) returned %v; want %v", compactJson(test.removal), compactJson(kb), got, test.want)
+			}
+			if got := removesAny(test.removal, []*Keybinding{{Key: "f1", Command: "other"}, kb}); got != test.want {
+				t.Errorf("removesAny(%s) returned %v; want %v", compactJson(test.removal), got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnmatchedRemovals(t *testing.T) {
+	defaults := []*Keybinding{
+		{Key: "ctrl+k ctrl+c", Command: "editor.action.addCommentLine", When: "editorTextFocus && !editorReadonly"},
+		{Key: "ctrl+k ctrl+u", Command: "editor.action.removeCommentLine", When: "editorTextFocus && !editorReadonly"},
+		{Key: "ctrl+/", Command: "editor.action.commentLine", When: "editorTextFocus && !editorReadonly"},
+		{Key: "ctrl+numpad_divide", Command: "editor.action.commentLine", When: "editorTextFocus && !editorReadonly"},
+	}
+
+	for _, test := range []struct {
+		name string
+		kbs  []*Keybinding
+		want []string
+	}{
+		{
+			name: "ignores non-removals",
+			kbs: []*Keybinding{
+				{Key: "ctrl+x", Command: "groog.cut"},
+			},
+		},
+		{
+			name: "matched removals",
+			kbs: []*Keybinding{
+				{Key: "ctrl+k ctrl+c", Command: "-editor.action.addCommentLine"},
+				{Key: "ctrl+k ctrl+u", Command: "-editor.action.removeCommentLine", When: "editorTextFocus && !editorReadonly"},
+				{Command: "-editor.action.commentLine"},
+			},
+		},
+		{
+			name: "removal with a different key",
+			kbs: []*Keybinding{
+				{Key: "ctrl+k c", Command: "-editor.action.commentLine"},
+			},
+			want: []string{
+				"`ctrl+k c` → `-editor.action.commentLine` matches no default keybinding; use one of the following to remove the default keybinding:",
+				`  {"key":"ctrl+/","command":"-editor.action.commentLine","when":"editorTextFocus && !editorReadonly"}`,
+				`  {"key":"ctrl+numpad_divide","command":"-editor.action.commentLine","when":"editorTextFocus && !editorReadonly"}`,
+			},
+		},
+		{
+			name: "removal with a different when clause",
+			kbs: []*Keybinding{
+				{Command: "-editor.action.addCommentLine", When: "editorTextFocus"},
+			},
+			want: []string{
+				"`` → `-editor.action.addCommentLine` when `editorTextFocus` matches no default keybinding; use one of the following to remove the default keybinding:",
+				`  {"key":"ctrl+k ctrl+c","command":"-editor.action.addCommentLine","when":"editorTextFocus && !editorReadonly"}`,
+			},
+		},
+		{
+			name: "command without default keybindings",
+			kbs: []*Keybinding{
+				{Key: "ctrl+l", Command: "-editor.action.selectAll"},
+			},
+			want: []string{
+				"`ctrl+l` → `-editor.action.selectAll` matches no default keybinding; editor.action.selectAll has no default keybindings",
+			},
+		},
+		{
+			name: "extension command without default keybindings",
+			kbs: []*Keybinding{
+				{Key: "ctrl+l", Command: "-gitlens.toggleLineBlame"},
+			},
+			want: []string{
+				"`ctrl+l` → `-gitlens.toggleLineBlame` matches no default keybinding; gitlens.toggleLineBlame has no default keybindings (is its extension installed?)",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			report, n := removalReport(test.kbs, defaults)
+
+			var want string
+			if len(test.want) > 0 {
+				want = strings.Join(test.want, "\n") + "\n"
+			}
+			if report != want {
+				t.Errorf("removalReport() returned report:\n%s\nwant:\n%s", report, want)
+			}
+			var wantN int
+			for _, line := range test.want {
+				if !strings.HasPrefix(line, " ") {
+					wantN++
+				}
+			}
+			if n != wantN {
+				t.Errorf("removalReport() returned %d unmatched removals; want %d", n, wantN)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// keybindingScenario is a keypress (or chord) and the command it should run
+// in a context.
+type keybindingScenario struct {
+	Name string `json:"name"`
+	// Layout is the keyboard layout (defaults to defaultKeyboardLayout).
+	Layout  string                 `json:"layout"`
+	Context map[string]interface{} `json:"context"`
+	// Keys are the keys pressed in sequence (e.g. `["ctrl+x", "s"]`).
+	Keys []string `json:"keys"`
+	// Command is the expected command (or empty if no command should run).
+	Command string                 `json:"command"`
+	Args    map[string]interface{} `json:"args"`
+}
+
+func TestKeybindingScenarios(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "scenarios", "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list scenario files: %v", err)
+	}
+
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read scenario file: %v", err)
+		}
+
+		var scenarios []*keybindingScenario
+		if err := json.Unmarshal(contents, &scenarios); err != nil {
+			t.Fatalf("failed to unmarshal scenario file %s: %v", file, err)
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(filepath.Base(file)+"/"+scenario.Name, func(t *testing.T) {
+				layout, err := selectKeyboardLayout(scenario.Layout)
+				if err != nil {
+					t.Fatalf("selectKeyboardLayout(%q) returned error: %v", scenario.Layout, err)
+				}
+
+				key := strings.Join(scenario.Keys, " ")
+				s, err := simulateKeypress(key, scenario.Context, nil, kbDefsToBindings(layout))
+				if err != nil {
+					t.Fatalf("simulateKeypress(%q) returned error: %v", key, err)
+				}
+
+				gotCommand, gotArgs := "", normalizedArgs(t, nil)
+				if s.winner != nil {
+					gotCommand = s.winner.binding.Command
+					gotArgs = normalizedArgs(t, s.winner.binding.Args)
+				}
+				wantArgs := normalizedArgs(t, scenario.Args)
+				if gotCommand != scenario.Command || gotArgs != wantArgs {
+					t.Errorf("%q ran (%q, %s); want (%q, %s)\n%s", key, gotCommand, gotArgs, scenario.Command, wantArgs, s)
+				}
+			})
+		}
+	}
+}
+
+// normalizedArgs returns the json representation of the args (after a json
+// round trip so generated args and args from scenario files are comparable).
+func normalizedArgs(t *testing.T, args map[string]interface{}) string {
+	t.Helper()
+
+	if len(args) == 0 {
+		return "{}"
+	}
+	b, err := json.Marshal(args)
+	if err != nil {
+		t.Fatalf("failed to marshal args: %v", err)
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatalf("failed to unmarshal args: %v", err)
+	}
+	return compactJson(v)
+}
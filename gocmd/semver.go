@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// See the following link for the semantic versioning specification:
+// https://semver.org
+
+var (
+	semverRegex = regexp.MustCompile(`^(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+)
+
+const (
+	patchVersion = iota
+	minorVersion
+	majorVersion
+)
+
+// Version is a semantic version (e.g. `2.8.0-beta.1+abc123`).
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      []string
+}
+
+func parseVersion(s string) (*Version, error) {
+	m := semverRegex.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid semantic version %q", s)
+	}
+
+	v := &Version{}
+	for i, n := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		var err error
+		if *n, err = strconv.Atoi(m[i+1]); err != nil {
+			return nil, fmt.Errorf("invalid semantic version %q: %v", s, err)
+		}
+	}
+	if m[4] != "" {
+		v.Prerelease = strings.Split(m[4], ".")
+		for _, id := range v.Prerelease {
+			if isNumericIdentifier(id) && len(id) > 1 && id[0] == '0' {
+				return nil, fmt.Errorf("invalid semantic version %q: numeric pre-release identifiers must not have leading zeros", s)
+			}
+		}
+	}
+	if m[5] != "" {
+		v.Build = strings.Split(m[5], ".")
+	}
+	return v, nil
+}
+
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// bump returns the next version for the provided section (patchVersion,
+// minorVersion, or majorVersion). If prerelease is set (e.g. `beta`), then
+// the result is a pre-release of the next version (e.g. `2.8.0-beta.1`), or
+// the next pre-release if v is already a pre-release of that version with the
+// same tag (e.g. `2.8.0-beta.2`).
+//
+// Similar to `npm version`, bumping a pre-release version without a
+// pre-release tag releases that version if the section is already bumped
+// (e.g. a minor bump of `2.8.0-beta.2` results in `2.8.0`).
+func (v *Version) bump(section int, prerelease string) (*Version, error) {
+	if prerelease != "" && (!semverRegex.MatchString(fmt.Sprintf("0.0.0-%s", prerelease)) || strings.Contains(prerelease, ".")) {
+		return nil, fmt.Errorf("invalid pre-release tag %q", prerelease)
+	}
+
+	r := &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	isPrerelease := len(v.Prerelease) > 0
+	switch section {
+	case majorVersion:
+		if !isPrerelease || v.Minor != 0 || v.Patch != 0 {
+			r.Major, r.Minor, r.Patch = v.Major+1, 0, 0
+		}
+	case minorVersion:
+		if !isPrerelease || v.Patch != 0 {
+			r.Minor, r.Patch = v.Minor+1, 0
+		}
+	case patchVersion:
+		if !isPrerelease {
+			r.Patch = v.Patch + 1
+		}
+	default:
+		return nil, fmt.Errorf("unknown version section %d", section)
+	}
+
+	if prerelease == "" {
+		return r, nil
+	}
+
+	// Only continue the pre-release sequence if the version itself is unchanged.
+	sameCore := r.Major == v.Major && r.Minor == v.Minor && r.Patch == v.Patch
+	if sameCore && len(v.Prerelease) == 2 && v.Prerelease[0] == prerelease && isNumericIdentifier(v.Prerelease[1]) {
+		n, err := strconv.Atoi(v.Prerelease[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pre-release number: %v", err)
+		}
+		r.Prerelease = []string{prerelease, strconv.Itoa(n + 1)}
+		return r, nil
+	}
+	r.Prerelease = []string{prerelease, "1"}
+	return r, nil
+}
+
+// compare returns a negative number if v has lower precedence than other, a
+// positive number if it has higher precedence, and zero if they have the same
+// precedence (build metadata is ignored).
+func (v *Version) compare(other *Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d != 0 {
+			return d
+		}
+	}
+
+	// A pre-release version has lower precedence than the release.
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		a, b := v.Prerelease[i], other.Prerelease[i]
+		if a == b {
+			continue
+		}
+		aNum, bNum := isNumericIdentifier(a), isNumericIdentifier(b)
+		switch {
+		case aNum && bNum:
+			an, _ := strconv.Atoi(a)
+			bn, _ := strconv.Atoi(b)
+			return an - bn
+		case aNum:
+			return -1
+		case bNum:
+			return 1
+		}
+		return strings.Compare(a, b)
+	}
+	return len(v.Prerelease) - len(other.Prerelease)
+}
+
+func isNumericIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	for _, test := range []struct {
+		version string
+		wantErr bool
+	}{
+		{version: "1.2.3"},
+		{version: "0.0.0"},
+		{version: "2.8.0-beta.1"},
+		{version: "2.8.0-beta.1+abc.123"},
+		{version: "1.0.0+build"},
+		{version: "1.2", wantErr: true},
+		{version: "01.2.3", wantErr: true},
+		{version: "1.2.3-beta.01", wantErr: true},
+		{version: "v1.2.3", wantErr: true},
+	} {
+		t.Run(test.version, func(t *testing.T) {
+			v, err := parseVersion(test.version)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseVersion(%q) returned error %v; want error: %v", test.version, err, test.wantErr)
+			}
+			if err == nil && v.String() != test.version {
+				t.Errorf("parseVersion(%q).String() returned %q", test.version, v.String())
+			}
+		})
+	}
+}
+
+func TestVersionBump(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		version    string
+		section    int
+		prerelease string
+		want       string
+		wantErr    bool
+	}{
+		{name: "patch", version: "2.8.3", section: patchVersion, want: "2.8.4"},
+		{name: "minor", version: "2.8.3", section: minorVersion, want: "2.9.0"},
+		{name: "major", version: "2.8.3", section: majorVersion, want: "3.0.0"},
+		{name: "drops build metadata", version: "2.8.3+abc", section: patchVersion, want: "2.8.4"},
+		{name: "new pre-release", version: "2.7.4", section: minorVersion, prerelease: "beta", want: "2.8.0-beta.1"},
+		{name: "next pre-release", version: "2.8.0-beta.1", section: minorVersion, prerelease: "beta", want: "2.8.0-beta.2"},
+		{name: "next pre-release for patch", version: "2.8.0-beta.1", section: patchVersion, prerelease: "beta", want: "2.8.0-beta.2"},
+		{name: "major bump of pre-release with same tag", version: "2.8.0-beta.1", section: majorVersion, prerelease: "beta", want: "3.0.0-beta.1"},
+		{name: "minor bump of patch pre-release with same tag", version: "2.8.1-beta.3", section: minorVersion, prerelease: "beta", want: "2.9.0-beta.1"},
+		{name: "different pre-release tag", version: "2.8.0-alpha.4", section: minorVersion, prerelease: "beta", want: "2.8.0-beta.1"},
+		{name: "release pre-release", version: "2.8.0-beta.2", section: minorVersion, want: "2.8.0"},
+		{name: "release patch pre-release", version: "2.8.1-beta.2", section: patchVersion, want: "2.8.1"},
+		{name: "major bump of minor pre-release", version: "2.8.0-beta.2", section: majorVersion, want: "3.0.0"},
+		{name: "release major pre-release", version: "3.0.0-rc.1", section: majorVersion, want: "3.0.0"},
+		{name: "invalid pre-release tag", version: "2.8.0", section: patchVersion, prerelease: "beta.1", wantErr: true},
+		{name: "invalid pre-release characters", version: "2.8.0", section: patchVersion, prerelease: "be_ta", wantErr: true},
+		{name: "unknown section", version: "2.8.0", section: 3, wantErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := parseVersion(test.version)
+			if err != nil {
+				t.Fatalf("parseVersion(%q) returned error: %v", test.version, err)
+			}
+
+			got, err := v.bump(test.section, test.prerelease)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("bump(%d, %q) returned error %v; want error: %v", test.section, test.prerelease, err, test.wantErr)
+			}
+			if err == nil && got.String() != test.want {
+				t.Errorf("%s.bump(%d, %q) returned %s; want %s", test.version, test.section, test.prerelease, got, test.want)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// Versions in increasing order of precedence (from the semver spec).
+	versions := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+	for i, a := range versions {
+		for j, b := range versions {
+			av, err := parseVersion(a)
+			if err != nil {
+				t.Fatalf("parseVersion(%q) returned error: %v", a, err)
+			}
+			bv, err := parseVersion(b)
+			if err != nil {
+				t.Fatalf("parseVersion(%q) returned error: %v", b, err)
+			}
+
+			got := av.compare(bv)
+			if (got < 0) != (i < j) || (got == 0) != (i == j) {
+				t.Errorf("%s.compare(%s) returned %d", a, b, got)
+			}
+		}
+	}
+
+	a, _ := parseVersion("1.0.0+abc")
+	b, _ := parseVersion("1.0.0+def")
+	if got := a.compare(b); got != 0 {
+		t.Errorf("compare() returned %d for versions that only differ in build metadata; want 0", got)
+	}
+}
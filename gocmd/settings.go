@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// generatedSettingsFile is the file (relative to the groog root directory)
+	// that contains the fixed settings (see fixedSettings). It is read by
+	// src/settings.ts.
+	generatedSettingsFile = "src/generated-settings.json"
+
+	// vscodeWordSeparators is the default value of the `editor.wordSeparators`
+	// setting.
+	vscodeWordSeparators = "`~!@#$%^&*()-=+[{]}\\|;:'\",.<>/?"
+)
+
+// GroogSetting is a setting that is set by the `groog.updateSettings` command
+// (see GroogSetting in src/settings.ts).
+type GroogSetting struct {
+	Section    string      `json:"section"`
+	Subsection string      `json:"subsection"`
+	Value      interface{} `json:"value"`
+	// LanguageID is the language the setting is scoped to (if any).
+	LanguageID string `json:"languageId,omitempty"`
+}
+
+func groogSetting(section, subsection string, value interface{}) *GroogSetting {
+	return &GroogSetting{section, subsection, value, ""}
+}
+
+func languageSetting(languageID, section, subsection string, value interface{}) *GroogSetting {
+	return &GroogSetting{section, subsection, value, languageID}
+}
+
+// colorCustomizationSetting returns the workbench color customizations with
+// the provided line highlight border color.
+func colorCustomizationSetting(color string) *GroogSetting {
+	return groogSetting("workbench", "colorCustomizations", map[string]interface{}{
+		"editorGutter.background":               "#000000",
+		"editorLineNumber.activeForeground":     "#00ffff",
+		"editor.lineHighlightBorder":            color,
+		"terminal.findMatchHighlightBackground": "#00bbbb",
+		"terminal.findMatchBackground":          "#bb00bb",
+	})
+}
+
+// wordSeparatorSetting adds the provided characters to the default editor word
+// separators.
+func wordSeparatorSetting(addCharacters string) *GroogSetting {
+	separators := vscodeWordSeparators
+	for _, c := range addCharacters {
+		if !strings.ContainsRune(separators, c) {
+			separators += string(c)
+		}
+	}
+	return groogSetting("editor", "wordSeparators", separators)
+}
+
+// editorSettings returns the settings for the default value of the
+// `groog.editorSettings` configuration.
+func editorSettings() ([]*GroogSetting, error) {
+	values, ok := editorSettingsSchema().Options["default"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("groog.editorSettings default is not an object")
+	}
+
+	var settings []*GroogSetting
+	for _, k := range sortedKeys(values) {
+		settings = append(settings, groogSetting("editor", k, values[k]))
+	}
+	return settings, nil
+}
+
+// groogSettings returns the settings that are set by the
+// `groog.updateSettings` command. Settings that depend on the workspace
+// (e.g. `coverage-gutters.manualCoverageFilePaths`) are not included.
+func groogSettings() ([]*GroogSetting, error) {
+	settings, err := editorSettings()
+	if err != nil {
+		return nil, err
+	}
+	settings = append(settings, fixedSettings()...)
+	return append(settings, wordSeparatorSetting("_")), nil
+}
+
+// fixedSettings returns the settings that don't depend on the user's
+// configuration (unlike the `groog.editorSettings` values and the word
+// separators, which are added to the existing separators) or the workspace.
+// They are written to generatedSettingsFile for src/settings.ts.
+func fixedSettings() []*GroogSetting {
+	return []*GroogSetting{
+		groogSetting("window", "newWindowDimensions", "maximized"),
+		groogSetting("files", "eol", "\n"),
+		groogSetting("files", "insertFinalNewline", true),
+		groogSetting("files", "trimFinalNewlines", true),
+		groogSetting("files", "trimTrailingWhitespace", true),
+		// true is the default, but explicitly set it here to avoid potential issues.
+		groogSetting("terminal", "integrated.allowChords", true),
+		groogSetting("terminal", "integrated.commandsToSkipShell", []string{
+			"workbench.action.terminal.sendSequence",
+			"groog.message.info",
+			"workbench.action.closePanel",
+			"workbench.action.terminal.focusNext",
+			"workbench.action.terminal.focusPrevious",
+			"workbench.action.terminal.newWithProfile",
+			"groog.terminal.find",
+			"groog.terminal.reverseFind",
+			"workbench.action.terminal.focusFind",
+			"workbench.action.terminal.findNext",
+			"workbench.action.terminal.findPrevious",
+			"groog.ctrlG",
+			"groog.multiCommand.execute",
+			"termin-all-or-nothing.closePanel",
+			"workbench.action.toggleAuxiliaryBar",
+			"workbench.panel.chat.view.copilot.focus",
+		}),
+		groogSetting("terminal", "integrated.copyOnSelection", true),
+		groogSetting("terminal", "integrated.scrollback", 10_000),
+		colorCustomizationSetting("#707070"),
+		groogSetting("workbench", "editor.limit.enabled", true),
+		groogSetting("workbench", "editor.limit.perEditorGroup", true),
+		groogSetting("workbench", "editor.limit.value", 1),
+		groogSetting("workbench", "editor.showTabs", false),
+		groogSetting("workbench", "startupEditor", "none"),
+		groogSetting("terminal", "integrated.defaultProfile.windows", "PowerShell"),
+		// Don't start a powershell terminal when opening a powershell script.
+		groogSetting("powershell", "startAutomatically", false),
+		groogSetting("terminal", "integrated.automationProfile.windows", map[string]interface{}{
+			"path": `C:\WINDOWS\System32\WindowsPowerShell\v1.0\powershell.exe`,
+		}),
+		// https://github.com/golang/vscode-go/issues/217
+		groogSetting("gopls", "analyses", map[string]interface{}{"composites": false}),
+		languageSetting("typescript", "editor", "formatOnSave", true),
+		// MinGW terminal (set `terminal.integrated.defaultProfile.windows` to
+		// `MinGW` to make it the default terminal).
+		// https://code.visualstudio.com/docs/terminal/basics#_terminal-profiles
+		groogSetting("terminal", "integrated.profiles.windows", map[string]interface{}{
+			"MinGW": map[string]interface{}{
+				"path":         `C:\msys64\usr\bin\bash.exe`,
+				"overrideName": true,
+				"color":        "terminal.ansiGreen",
+				"icon":         "hubot",
+				"args":         []string{"--login", "-i"},
+				"env": map[string]interface{}{
+					"GROOG_VSCODE": "1",
+				},
+			},
+		}),
+
+		// Coverage Gutters settings
+		groogSetting("coverage-gutters", "showLineCoverage", true),
+		groogSetting("coverage-gutters", "showGutterCoverage", false),
+		groogSetting("coverage-gutters", "showRulerCoverage", true),
+
+		// Very Import-Ant settings
+		groogSetting("very-import-ant", "format.enable", true),
+		groogSetting("very-import-ant", "organizeImports", true),
+		groogSetting("very-import-ant", "onTypeTriggerCharacters", "\n,.\t []{}"),
+		groogSetting("very-import-ant", "removeUnusedImports", true),
+		groogSetting("very-import-ant", "output.enable", false),
+		groogSetting("ruff", "organizeImports", false),
+		languageSetting("python", "editor", "formatOnSave", true),
+		languageSetting("python", "editor", "formatOnType", true),
+		languageSetting("python", "editor", "defaultFormatter", "groogle.very-import-ant"),
+		groogSetting("python", "analysis.autoIndent", false),
+		groogSetting("python", "analysis.autoFormatStrings", false),
+		groogSetting("notebook", "formatOnSave.enabled", true),
+	}
+}
+
+// generatedSettings returns the contents of generatedSettingsFile.
+func generatedSettings() ([]byte, error) {
+	settings := fixedSettings()
+	// Check for duplicate settings.
+	if _, err := settingsJSON(settings); err != nil {
+		return nil, err
+	}
+	return marshalJson(settings)
+}
+
+// settingsJSON returns the settings.json contents for the settings. Language
+// settings are grouped under `[<language>]` keys.
+func settingsJSON(settings []*GroogSetting) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	for _, s := range settings {
+		target := m
+		if s.LanguageID != "" {
+			lk := fmt.Sprintf("[%s]", s.LanguageID)
+			lm, ok := m[lk].(map[string]interface{})
+			if !ok {
+				lm = map[string]interface{}{}
+				m[lk] = lm
+			}
+			target = lm
+		}
+
+		key := fmt.Sprintf("%s.%s", s.Section, s.Subsection)
+		if _, ok := target[key]; ok {
+			return nil, fmt.Errorf("duplicate setting %q", key)
+		}
+		target[key] = s.Value
+	}
+	return m, nil
+}
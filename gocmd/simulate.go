@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// See the following link for details on how VS Code resolves keybindings:
+// https://code.visualstudio.com/docs/getstarted/keybindings#_keyboard-rules
+
+// simulatedRule is a keybinding rule considered by the simulator.
+type simulatedRule struct {
+	binding *Keybinding
+	// source is where the rule is defined (e.g. `groog`).
+	source string
+	// index is the (1-indexed) position of the rule in its source.
+	index int
+	// removedBy is the removal rule that removes this rule (if any).
+	removedBy *simulatedRule
+	// matches is whether the rule's when clause is true.
+	matches bool
+}
+
+func (sr *simulatedRule) String() string {
+	return fmt.Sprintf("%s (%s keybinding #%d)", keybindingString(sr.binding), sr.source, sr.index)
+}
+
+// simulation is the result of resolving a keypress.
+type simulation struct {
+	key string
+	// candidates are the rules for the key (in the order VS Code applies them).
+	candidates []*simulatedRule
+	// winner is the rule that is run (if any).
+	winner *simulatedRule
+	// chord is whether the key starts a chord (e.g. `ctrl+x` for `ctrl+x s`).
+	chord bool
+	// warnings are the when clause parts that couldn't be evaluated accurately.
+	warnings []string
+}
+
+// simulateKeypress resolves the key against the VS Code default keybindings
+// and the provided keybindings for the context values. Like VS Code, removal
+// rules are applied first and then the last rule whose when clause is true
+// wins.
+func simulateKeypress(key string, ctx whenContextValues, defaults, kbs []*Keybinding) (*simulation, error) {
+	var rules []*simulatedRule
+	for i, kb := range defaults {
+		rules = append(rules, &simulatedRule{binding: kb, source: "default", index: i + 1})
+	}
+	for i, kb := range kbs {
+		rules = append(rules, &simulatedRule{binding: kb, source: "groog", index: i + 1})
+	}
+
+	// Apply the removal rules.
+	for _, removal := range rules {
+		if !strings.HasPrefix(removal.binding.Command, "-") {
+			continue
+		}
+		for _, r := range rules {
+			if r.removedBy == nil && removes(removal.binding, r.binding) {
+				r.removedBy = removal
+			}
+		}
+	}
+
+	s := &simulation{key: key}
+	for _, r := range rules {
+		if strings.HasPrefix(r.binding.Command, "-") || r.removedBy != nil {
+			if normalizeKey(r.binding.Key) == normalizeKey(key) {
+				s.candidates = append(s.candidates, r)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(normalizeKey(r.binding.Key), normalizeKey(key)+" ") {
+			s.chord = true
+		}
+		if normalizeKey(r.binding.Key) != normalizeKey(key) {
+			continue
+		}
+
+		expr, err := parseWhenClause(r.binding.When)
+		if err != nil {
+			return nil, err
+		}
+		r.matches = expr.evaluate(ctx, func(w string) {
+			s.warnings = append(s.warnings, fmt.Sprintf("%s: %s", r, w))
+		})
+		if r.matches {
+			s.winner = r
+		}
+		s.candidates = append(s.candidates, r)
+	}
+	return s, nil
+}
+
+func (s *simulation) String() string {
+	var sb strings.Builder
+	switch {
+	case s.winner != nil:
+		fmt.Fprintf(&sb, "`%s` runs %s\n", s.key, keybindingActionString(s.winner.binding))
+		fmt.Fprintf(&sb, "Winning rule: %s\n", s.winner)
+	case s.chord:
+		fmt.Fprintf(&sb, "`%s` starts a chord (no rule for the key itself matches)\n", s.key)
+	default:
+		fmt.Fprintf(&sb, "`%s` doesn't match any rule\n", s.key)
+	}
+
+	if len(s.candidates) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("Rules for the key (in order):\n")
+	for _, r := range s.candidates {
+		var status string
+		switch {
+		case strings.HasPrefix(r.binding.Command, "-"):
+			status = "removal"
+		case r.removedBy != nil:
+			status = fmt.Sprintf("removed by %s", r.removedBy)
+		case r == s.winner:
+			status = "winner"
+		case r.matches:
+			status = "overridden"
+		default:
+			status = "when clause is false"
+		}
+		fmt.Fprintf(&sb, "  [%s] %s\n", status, r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSimulateKeypress(t *testing.T) {
+	defaults := []*Keybinding{
+		{Key: "ctrl+k", Command: "editor.action.deleteLines", When: "editorTextFocus"},
+		{Key: "ctrl+k ctrl+c", Command: "editor.action.addCommentLine", When: "editorTextFocus"},
+		{Key: "ctrl+f", Command: "actions.find", When: "editorFocus"},
+		{Key: "ctrl+f", Command: "workbench.action.terminal.focusFind", When: "terminalFocus"},
+		{Key: "ctrl+g", Command: "workbench.action.gotoLine"},
+		{Key: "ctrl+p", Command: "workbench.action.quickOpen"},
+	}
+
+	for _, test := range []struct {
+		name        string
+		key         string
+		ctx         whenContextValues
+		kbs         []*Keybinding
+		wantCommand string
+		wantChord   bool
+		want        []string
+	}{
+		{
+			name:        "default keybinding",
+			key:         "ctrl+f",
+			ctx:         whenContextValues{"editorFocus": true},
+			wantCommand: "actions.find",
+			want: []string{
+				"`ctrl+f` runs `actions.find`",
+				"Winning rule: `ctrl+f` → `actions.find` when `editorFocus` (default keybinding #3)",
+				"Rules for the key (in order):",
+				"  [winner] `ctrl+f` → `actions.find` when `editorFocus` (default keybinding #3)",
+				"  [when clause is false] `ctrl+f` → `workbench.action.terminal.focusFind` when `terminalFocus` (default keybinding #4)",
+			},
+		},
+		{
+			name: "last matching rule wins",
+			key:  "ctrl+f",
+			ctx:  whenContextValues{"editorFocus": true, "groog.context.findMode": true},
+			kbs: []*Keybinding{
+				{Key: "ctrl+f", Command: "groog.find", When: "editorFocus"},
+				{Key: "ctrl+f", Command: "groog.find.next", When: "groog.context.findMode"},
+				{Key: "ctrl+f", Command: "groog.terminal.find", When: "terminalFocus"},
+			},
+			wantCommand: "groog.find.next",
+			want: []string{
+				"`ctrl+f` runs `groog.find.next`",
+				"Winning rule: `ctrl+f` → `groog.find.next` when `groog.context.findMode` (groog keybinding #2)",
+				"Rules for the key (in order):",
+				"  [overridden] `ctrl+f` → `actions.find` when `editorFocus` (default keybinding #3)",
+				"  [when clause is false] `ctrl+f` → `workbench.action.terminal.focusFind` when `terminalFocus` (default keybinding #4)",
+				"  [overridden] `ctrl+f` → `groog.find` when `editorFocus` (groog keybinding #1)",
+				"  [winner] `ctrl+f` → `groog.find.next` when `groog.context.findMode` (groog keybinding #2)",
+				"  [when clause is false] `ctrl+f` → `groog.terminal.find` when `terminalFocus` (groog keybinding #3)",
+			},
+		},
+		{
+			name: "removal rule",
+			key:  "ctrl+g",
+			kbs: []*Keybinding{
+				{Key: "ctrl+g", Command: "-workbench.action.gotoLine"},
+			},
+			want: []string{
+				"`ctrl+g` doesn't match any rule",
+				"Rules for the key (in order):",
+				"  [removed by `ctrl+g` → `-workbench.action.gotoLine` (groog keybinding #1)] `ctrl+g` → `workbench.action.gotoLine` (default keybinding #5)",
+				"  [removal] `ctrl+g` → `-workbench.action.gotoLine` (groog keybinding #1)",
+			},
+		},
+		{
+			name: "removal rule with a different when clause",
+			key:  "ctrl+f",
+			ctx:  whenContextValues{"editorFocus": true},
+			kbs: []*Keybinding{
+				{Key: "ctrl+f", Command: "-actions.find", When: "terminalFocus"},
+			},
+			wantCommand: "actions.find",
+			want: []string{
+				"`ctrl+f` runs `actions.find`",
+				"Winning rule: `ctrl+f` → `actions.find` when `editorFocus` (default keybinding #3)",
+				"Rules for the key (in order):",
+				"  [winner] `ctrl+f` → `actions.find` when `editorFocus` (default keybinding #3)",
+				"  [when clause is false] `ctrl+f` → `workbench.action.terminal.focusFind` when `terminalFocus` (default keybinding #4)",
+				"  [removal] `ctrl+f` → `-actions.find` when `terminalFocus` (groog keybinding #1)",
+			},
+		},
+		{
+			name: "removal rule without a key",
+			key:  "ctrl+p",
+			kbs: []*Keybinding{
+				{Command: "-workbench.action.quickOpen"},
+				{Key: "ctrl+p", Command: "groog.cursorUp", When: "editorTextFocus"},
+			},
+			want: []string{
+				"`ctrl+p` doesn't match any rule",
+				"Rules for the key (in order):",
+				"  [removed by `` → `-workbench.action.quickOpen` (groog keybinding #1)] `ctrl+p` → `workbench.action.quickOpen` (default keybinding #6)",
+				"  [when clause is false] `ctrl+p` → `groog.cursorUp` when `editorTextFocus` (groog keybinding #2)",
+			},
+		},
+		{
+			name:      "chord prefix",
+			key:       "ctrl+k",
+			ctx:       whenContextValues{"editorFocus": true},
+			wantChord: true,
+			want: []string{
+				"`ctrl+k` starts a chord (no rule for the key itself matches)",
+				"Rules for the key (in order):",
+				"  [when clause is false] `ctrl+k` → `editor.action.deleteLines` when `editorTextFocus` (default keybinding #1)",
+			},
+		},
+		{
+			name:        "chord prefix with a matching rule",
+			key:         "ctrl+k",
+			ctx:         whenContextValues{"editorTextFocus": true},
+			wantCommand: "editor.action.deleteLines",
+			wantChord:   true,
+			want: []string{
+				"`ctrl+k` runs `editor.action.deleteLines`",
+				"Winning rule: `ctrl+k` → `editor.action.deleteLines` when `editorTextFocus` (default keybinding #1)",
+				"Rules for the key (in order):",
+				"  [winner] `ctrl+k` → `editor.action.deleteLines` when `editorTextFocus` (default keybinding #1)",
+			},
+		},
+		{
+			name:        "full chord",
+			key:         "ctrl+k  Ctrl+C",
+			ctx:         whenContextValues{"editorTextFocus": true},
+			wantCommand: "editor.action.addCommentLine",
+			want: []string{
+				"`ctrl+k  Ctrl+C` runs `editor.action.addCommentLine`",
+				"Winning rule: `ctrl+k ctrl+c` → `editor.action.addCommentLine` when `editorTextFocus` (default keybinding #2)",
+				"Rules for the key (in order):",
+				"  [winner] `ctrl+k ctrl+c` → `editor.action.addCommentLine` when `editorTextFocus` (default keybinding #2)",
+			},
+		},
+		{
+			name: "removed chord doesn't start a chord",
+			key:  "ctrl+k",
+			kbs: []*Keybinding{
+				{Key: "ctrl+k ctrl+c", Command: "-editor.action.addCommentLine"},
+			},
+			want: []string{
+				"`ctrl+k` doesn't match any rule",
+				"Rules for the key (in order):",
+				"  [when clause is false] `ctrl+k` → `editor.action.deleteLines` when `editorTextFocus` (default keybinding #1)",
+			},
+		},
+		{
+			name: "unbound key",
+			key:  "ctrl+q",
+			want: []string{
+				"`ctrl+q` doesn't match any rule",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := simulateKeypress(test.key, test.ctx, defaults, test.kbs)
+			if err != nil {
+				t.Fatalf("simulateKeypress() returned error: %v", err)
+			}
+
+			var gotCommand string
+			if s.winner != nil {
+				gotCommand = s.winner.binding.Command
+			}
+			if gotCommand != test.wantCommand {
+				t.Errorf("simulateKeypress() winner ran %q; want %q", gotCommand, test.wantCommand)
+			}
+			if s.chord != test.wantChord {
+				t.Errorf("simulateKeypress() returned chord %v; want %v", s.chord, test.wantChord)
+			}
+			if want := strings.Join(test.want, "\n") + "\n"; s.String() != want {
+				t.Errorf("simulateKeypress() returned:\n%s\nwant:\n%s", s, want)
+			}
+			if len(s.warnings) > 0 {
+				t.Errorf("simulateKeypress() returned unexpected warnings: %v", s.warnings)
+			}
+		})
+	}
+}
+
+func TestSimulateKeypressInWarning(t *testing.T) {
+	defaults := []*Keybinding{
+		{Key: "f5", Command: "workbench.action.debug.start", When: "debuggersAvailable && resourceScheme in debugSchemes"},
+		{Key: "f5", Command: "workbench.action.debug.continue", When: "inDebugMode"},
+	}
+
+	s, err := simulateKeypress("f5", whenContextValues{"debuggersAvailable": true}, defaults, nil)
+	if err != nil {
+		t.Fatalf("simulateKeypress() returned error: %v", err)
+	}
+
+	want := []string{
+		"`f5` → `workbench.action.debug.start` when `debuggersAvailable && resourceScheme in debugSchemes` (default keybinding #1): `resourceScheme in debugSchemes` is treated as false since array and object context values aren't supported",
+	}
+	if strings.Join(s.warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("simulateKeypress() returned warnings:\n%s\nwant:\n%s", strings.Join(s.warnings, "\n"), strings.Join(want, "\n"))
+	}
+}
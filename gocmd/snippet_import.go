@@ -0,0 +1,699 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// This file contains logic for converting snippets from other editors
+// (UltiSnips, yasnippet, and TextMate) into SnippetFiles.
+
+// importedSnippets is the result of converting an external snippet library.
+type importedSnippets struct {
+	// Language is the VS Code language identifier inferred from the source files
+	// (empty for global snippets).
+	Language string
+	// Inferred is whether the language could be inferred from the source files.
+	Inferred bool
+	Snippets []*SnippetDefinition
+	// Issues is a list of constructs that couldn't be translated.
+	Issues []string
+}
+
+func (is *importedSnippets) issuef(file, name, format string, a ...interface{}) {
+	is.Issues = append(is.Issues, fmt.Sprintf("%s: %q: %s", file, name, fmt.Sprintf(format, a...)))
+}
+
+// add lints the snippet and adds it to the list of imported snippets.
+func (is *importedSnippets) add(file string, s *SnippetDefinition) {
+	if issues := lintSnippet(s); len(issues) > 0 {
+		is.issuef(file, s.Name, "skipped snippet with invalid body: %s", strings.Join(issues, "; "))
+		return
+	}
+
+	// VS Code snippet names must be unique within a file.
+	name := s.Name
+	for i := 2; is.hasName(s.Name); i++ {
+		s.Name = fmt.Sprintf("%s (%d)", name, i)
+	}
+	is.Snippets = append(is.Snippets, s)
+}
+
+// addFileLanguage sets the language of the snippets that were added since the
+// provided index (i.e. the snippets from a single source file). The language is
+// stored in the snippet scope until mergeLanguages is called.
+func (is *importedSnippets) addFileLanguage(start int, language string) {
+	for _, s := range is.Snippets[start:] {
+		s.Scope = language
+	}
+}
+
+// mergeLanguages sets the import language from the languages of the source
+// files. If the source files have different languages (e.g. `go.snippets` and
+// `all.snippets`), then the snippets are imported as global snippets that are
+// scoped to their source file's language.
+func (is *importedSnippets) mergeLanguages(path string) {
+	languages := map[string]bool{}
+	for _, s := range is.Snippets {
+		languages[s.Scope] = true
+	}
+
+	is.Inferred = true
+	if len(languages) > 1 {
+		var names []string
+		for _, l := range sortedKeys(languages) {
+			if l == "" {
+				l = "global"
+			}
+			names = append(names, l)
+		}
+		is.Issues = append(is.Issues, fmt.Sprintf("%s: source files have multiple languages %v; snippets are imported as global snippets scoped to their source file's language", path, names))
+		return
+	}
+
+	for l := range languages {
+		is.Language = l
+	}
+	for _, s := range is.Snippets {
+		s.Scope = ""
+	}
+}
+
+func (is *importedSnippets) hasName(name string) bool {
+	for _, s := range is.Snippets {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	snippetImporters = map[string]func(path string) (*importedSnippets, error){
+		"ultisnips": importUltiSnips,
+		"yasnippet": importYasnippet,
+		"textmate":  importTextMate,
+	}
+)
+
+// importSnippets converts the snippets at the provided path (file or
+// directory) in the provided format into a SnippetFile named name. If
+// language is empty, then the language is inferred from the source files.
+func importSnippets(snippetFormat, path, language, name string) (*SnippetFile, []string, error) {
+	importer, ok := snippetImporters[snippetFormat]
+	if !ok {
+		formats := maps.Keys(snippetImporters)
+		sort.Strings(formats)
+		return nil, nil, fmt.Errorf("unknown snippet format %q (expected one of %v)", snippetFormat, formats)
+	}
+
+	is, err := importer(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(is.Snippets) == 0 {
+		return nil, is.Issues, fmt.Errorf("no snippets could be imported from %s", path)
+	}
+	if language != "" {
+		// Scopes are only relevant for global snippet files.
+		for _, s := range is.Snippets {
+			s.Scope = ""
+		}
+	} else if is.Inferred {
+		language = is.Language
+	} else {
+		return nil, is.Issues, fmt.Errorf("failed to infer the language of %s; please provide it explicitly", path)
+	}
+
+	if name == "" {
+		fileLanguage := language
+		if fileLanguage == "" {
+			fileLanguage = "global"
+		}
+		name = fmt.Sprintf("%s-%s", snippetFormat, fileLanguage)
+	}
+	sf := &SnippetFile{
+		Path:     fmt.Sprintf("snippets/%s.json", name),
+		Language: language,
+		Snippets: is.Snippets,
+	}
+	for _, existing := range SnippetFiles {
+		if existing.Path == sf.Path {
+			return nil, is.Issues, fmt.Errorf("snippet file %s already exists", sf.Path)
+		}
+	}
+	return sf, is.Issues, nil
+}
+
+// writeImportedSnippetFile writes the snippet json file and a go file that
+// registers the snippet file in SnippetFiles.
+func writeImportedSnippetFile(root string, sf *SnippetFile) (string, error) {
+	src, err := sf.goSource()
+	if err != nil {
+		return "", err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(sf.Path), ".json")
+	goFile := filepath.Join(root, "gocmd", fmt.Sprintf("snippets_%s.go", strings.NewReplacer("-", "_", ".", "_").Replace(base)))
+	if _, err := os.Stat(goFile); err == nil {
+		return "", fmt.Errorf("file %s already exists", goFile)
+	}
+
+	m, err := sf.json()
+	if err != nil {
+		return "", err
+	}
+	b, err := marshalJson(m)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(sf.Path)), b, 0644); err != nil {
+		return "", fmt.Errorf("failed to write snippet file: %v", err)
+	}
+	if err := os.WriteFile(goFile, src, 0644); err != nil {
+		return "", fmt.Errorf("failed to write go file: %v", err)
+	}
+	return goFile, nil
+}
+
+// goSource returns the go code that registers the snippet file.
+func (sf *SnippetFile) goSource() ([]byte, error) {
+	var sb strings.Builder
+	sb.WriteString("package main\n\n")
+	sb.WriteString("// This file was generated by `vs-package snippet import`.\n\n")
+	sb.WriteString("func init() {\n")
+	sb.WriteString("SnippetFiles = append(SnippetFiles, &SnippetFile{\n")
+	fmt.Fprintf(&sb, "Path: %q,\n", sf.Path)
+	fmt.Fprintf(&sb, "Language: %q,\n", sf.Language)
+	sb.WriteString("Snippets: []*SnippetDefinition{\n")
+	for _, s := range sf.Snippets {
+		sb.WriteString("{\n")
+		fmt.Fprintf(&sb, "Name: %q,\n", s.Name)
+		fmt.Fprintf(&sb, "Prefixes: %#v,\n", s.Prefixes)
+		sb.WriteString("Body: []string{\n")
+		for _, line := range s.Body {
+			fmt.Fprintf(&sb, "%s,\n", goStringLiteral(line))
+		}
+		sb.WriteString("},\n")
+		if s.Description != "" {
+			fmt.Fprintf(&sb, "Description: %q,\n", s.Description)
+		}
+		if s.Scope != "" {
+			fmt.Fprintf(&sb, "Scope: %q,\n", s.Scope)
+		}
+		sb.WriteString("},\n")
+	}
+	sb.WriteString("},\n")
+	sb.WriteString("})\n")
+	sb.WriteString("}\n")
+
+	b, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated go code: %v", err)
+	}
+	return b, nil
+}
+
+// goStringLiteral returns a raw string literal when possible (since snippet
+// bodies often contain quotes and backslashes), and a quoted one otherwise.
+func goStringLiteral(s string) string {
+	if strings.ContainsAny(s, `"\`) && !strings.ContainsAny(s, "`\r") {
+		return fmt.Sprintf("`%s`", s)
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+/*************
+ * Languages *
+ *************/
+
+var (
+	// importedLanguageIDs maps vim filetypes, emacs major modes (without the
+	// `-mode` suffix), and TextMate scope names to VS Code language identifiers.
+	importedLanguageIDs = map[string]string{
+		"bash":        "shellscript",
+		"c++":         "cpp",
+		"cperl":       "perl",
+		"cs":          "csharp",
+		"csharp":      "csharp",
+		"dockerfile":  "dockerfile",
+		"js":          "javascript",
+		"js2":         "javascript",
+		"js3":         "javascript",
+		"make":        "makefile",
+		"md":          "markdown",
+		"objc":        "objective-c",
+		"py":          "python",
+		"rb":          "ruby",
+		"sh":          "shellscript",
+		"shell":       "shellscript",
+		"tex":         "latex",
+		"text":        "plaintext",
+		"ts":          "typescript",
+		"yml":         "yaml",
+		"zsh":         "shellscript",
+		"fundamental": "",
+		"all":         "",
+	}
+)
+
+// snippetLanguageID returns the VS Code language identifier for the provided
+// vim filetype, emacs mode, or TextMate scope.
+func snippetLanguageID(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	source = strings.TrimSuffix(source, "-mode")
+	source = strings.TrimSuffix(source, "-ts")
+	if parts := strings.Split(source, "."); len(parts) > 1 && (parts[0] == "source" || parts[0] == "text") {
+		source = parts[1]
+	}
+	if id, ok := importedLanguageIDs[source]; ok {
+		return id
+	}
+	return source
+}
+
+/*************
+ * UltiSnips *
+ *************/
+
+var (
+	ultiSnipsHeaderRegex = regexp.MustCompile(`^snippet\s+(.*)$`)
+	// Interpolations are delimited by unescaped backticks.
+	ultiSnipsInterpolationRegex = regexp.MustCompile("(^|[^\\\\])`")
+)
+
+func importUltiSnips(path string) (*importedSnippets, error) {
+	is := &importedSnippets{}
+	files, err := snippetSourceFiles(path, ".snippets")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		// UltiSnips files are named `ft.snippets`, `ft_*.snippets`, or `ft/*.snippets`.
+		ft := strings.SplitN(strings.TrimSuffix(filepath.Base(file), ".snippets"), "_", 2)[0]
+		if dir := filepath.Dir(file); file != filepath.Clean(path) && dir != filepath.Clean(path) && filepath.Base(dir) != "UltiSnips" {
+			ft = filepath.Base(dir)
+		}
+
+		start := len(is.Snippets)
+		if err := parseUltiSnipsFile(is, file); err != nil {
+			return nil, err
+		}
+		// Snippets in `all.snippets` apply to all filetypes.
+		is.addFileLanguage(start, snippetLanguageID(ft))
+	}
+	is.mergeLanguages(path)
+	return is, nil
+}
+
+func parseUltiSnipsFile(is *importedSnippets, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open snippet file: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	var lineNum int
+	nextLine := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		lineNum++
+		return scanner.Text(), true
+	}
+
+	for {
+		line, ok := nextLine()
+		if !ok {
+			break
+		}
+
+		switch {
+		case len(strings.TrimSpace(line)) == 0, strings.HasPrefix(line, "#"):
+		case ultiSnipsHeaderRegex.MatchString(line):
+			trigger, description, options := parseUltiSnipsHeader(ultiSnipsHeaderRegex.FindStringSubmatch(line)[1])
+			name := description
+			if name == "" {
+				name = trigger
+			}
+
+			var body []string
+			for {
+				l, ok := nextLine()
+				if !ok {
+					return fmt.Errorf("%s:%d: snippet %q is missing `endsnippet`", file, lineNum, trigger)
+				}
+				if strings.HasPrefix(l, "endsnippet") {
+					break
+				}
+				body = append(body, l)
+			}
+
+			skip := false
+			for _, o := range options {
+				switch o {
+				case 'r':
+					is.issuef(file, name, "skipped snippet with regular expression trigger")
+					skip = true
+				case 'e':
+					is.issuef(file, name, "skipped snippet with custom context")
+					skip = true
+				case 'b':
+					is.issuef(file, name, "ignored option %q (snippets can't be restricted to the beginning of a line)", o)
+				case 'A':
+					is.issuef(file, name, "ignored option %q (snippets can't be expanded automatically)", o)
+				case 'i', 'w':
+					is.issuef(file, name, "ignored option %q (snippets are expanded at word boundaries)", o)
+				case 't', 's', 'm':
+					is.issuef(file, name, "ignored whitespace option %q", o)
+				default:
+					is.issuef(file, name, "ignored unknown option %q", o)
+				}
+			}
+
+			body, ok := translateUltiSnipsBody(body)
+			if !ok {
+				is.issuef(file, name, "skipped snippet with embedded python, vim, or shell interpolation")
+				skip = true
+			}
+			if skip {
+				continue
+			}
+
+			is.add(file, &SnippetDefinition{
+				Name:        name,
+				Prefixes:    []string{trigger},
+				Body:        body,
+				Description: description,
+			})
+		case strings.HasPrefix(line, "global "):
+			is.issuef(file, "global", "python global blocks are not supported")
+			for {
+				if l, ok := nextLine(); !ok || strings.HasPrefix(l, "endglobal") {
+					break
+				}
+			}
+		default:
+			is.issuef(file, strings.Fields(line)[0], "ignored unsupported directive on line %d: %s", lineNum, line)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseUltiSnipsHeader parses the text after `snippet` into the trigger,
+// description, and options (following the same rules as UltiSnips).
+func parseUltiSnipsHeader(header string) (string, string, string) {
+	remain := strings.TrimSpace(header)
+
+	var options string
+	if words := strings.Fields(remain); len(words) > 2 {
+		last := words[len(words)-1]
+		rest := strings.TrimSpace(strings.TrimSuffix(remain, last))
+		if !strings.Contains(last, `"`) && strings.HasSuffix(rest, `"`) {
+			options, remain = last, rest
+		}
+	}
+
+	var description string
+	if len(strings.Fields(remain)) > 1 && strings.HasSuffix(remain, `"`) {
+		if left := strings.LastIndex(remain[:len(remain)-1], `"`); left > 0 {
+			description, remain = remain[left+1:len(remain)-1], remain[:left]
+		}
+	}
+
+	// Triggers with whitespace (and regex triggers) are wrapped in an arbitrary
+	// character (e.g. `!a b!`).
+	trigger := strings.TrimSpace(remain)
+	if (len(strings.Fields(trigger)) > 1 || strings.Contains(options, "r")) && len(trigger) > 1 {
+		trigger = trigger[1 : len(trigger)-1]
+	}
+	return trigger, description, options
+}
+
+// translateUltiSnipsBody converts an UltiSnips body into VS Code snippet
+// syntax. False is returned if the body contains interpolated code.
+func translateUltiSnipsBody(body []string) ([]string, bool) {
+	var r []string
+	for _, line := range body {
+		if ultiSnipsInterpolationRegex.MatchString(line) {
+			return nil, false
+		}
+		line = strings.ReplaceAll(line, "\\`", "`")
+		line = strings.ReplaceAll(line, "${VISUAL}", "${TM_SELECTED_TEXT}")
+		r = append(r, line)
+	}
+	return r, true
+}
+
+/*************
+ * yasnippet *
+ *************/
+
+var (
+	yasnippetDirectiveRegex = regexp.MustCompile(`^#\s*([a-z-]+)\s*:\s*(.*)$`)
+	// Embedded elisp is either `(backquoted)` or a `$(...)` mirror transformation.
+	yasnippetElispRegex = regexp.MustCompile("(^|[^\\\\])(`|\\$\\()")
+)
+
+func importYasnippet(path string) (*importedSnippets, error) {
+	is := &importedSnippets{}
+	files, err := snippetSourceFiles(path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		base := filepath.Base(file)
+		if base == ".yas-parents" || base == ".yas-make-groups" || base == ".yas-setup.el" {
+			is.issuef(file, base, "ignored unsupported yasnippet configuration file")
+			continue
+		}
+		if strings.HasPrefix(base, ".") || strings.HasSuffix(base, "~") {
+			continue
+		}
+
+		start := len(is.Snippets)
+		if err := parseYasnippetFile(is, file); err != nil {
+			return nil, err
+		}
+		// Snippets live in a directory named after the major mode (snippets for
+		// `fundamental-mode` apply to all modes).
+		is.addFileLanguage(start, snippetLanguageID(filepath.Base(filepath.Dir(file))))
+	}
+	is.mergeLanguages(path)
+	return is, nil
+}
+
+func parseYasnippetFile(is *importedSnippets, file string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read snippet file: %v", err)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+	directives := map[string]string{}
+	bodyStart := 0
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "# --" {
+			bodyStart = i + 1
+			break
+		}
+		if m := yasnippetDirectiveRegex.FindStringSubmatch(line); m != nil {
+			directives[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+
+	name := directives["name"]
+	if name == "" {
+		name = filepath.Base(file)
+	}
+	key := directives["key"]
+	if key == "" {
+		key = filepath.Base(file)
+	}
+
+	if directives["type"] == "command" {
+		is.issuef(file, name, "skipped command snippet (contains elisp)")
+		return nil
+	}
+	for _, d := range []string{"condition", "expand-env", "binding"} {
+		if _, ok := directives[d]; ok {
+			is.issuef(file, name, "ignored unsupported %q directive", d)
+		}
+	}
+
+	// yasnippet ignores the trailing newline of the file.
+	body := lines[bodyStart:]
+	if len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+	}
+	for _, line := range body {
+		if yasnippetElispRegex.MatchString(line) {
+			is.issuef(file, name, "skipped snippet with embedded elisp")
+			return nil
+		}
+	}
+	for i, line := range body {
+		body[i] = strings.ReplaceAll(line, "\\`", "`")
+	}
+
+	is.add(file, &SnippetDefinition{
+		Name:     name,
+		Prefixes: []string{key},
+		Body:     body,
+	})
+	return nil
+}
+
+/************
+ * TextMate *
+ ************/
+
+var (
+	// TextMate snippets can interpolate shell commands with backticks.
+	textMateShellRegex = regexp.MustCompile("(^|[^\\\\])`")
+)
+
+func importTextMate(path string) (*importedSnippets, error) {
+	is := &importedSnippets{}
+	files, err := snippetSourceFiles(path, ".tmSnippet")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snippet file: %v", err)
+		}
+		plist, err := parsePlistDict(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+
+		name := plist["name"]
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(file), ".tmSnippet")
+		}
+		trigger := plist["tabTrigger"]
+		if trigger == "" {
+			is.issuef(file, name, "skipped snippet without a tab trigger (key equivalents are not supported)")
+			continue
+		}
+
+		if scope := plist["scope"]; scope != "" {
+			scopes := textMateScopes(scope)
+			switch {
+			case len(scopes) == 0:
+				is.issuef(file, name, "ignored snippet scope %q without any scope names", scope)
+			case len(scopes) > 1:
+				is.issuef(file, name, "snippet scope %q has multiple scopes; only the first is used", scope)
+			}
+			if len(scopes) > 0 {
+				if lang := snippetLanguageID(scopes[0]); !is.Inferred {
+					is.Language, is.Inferred = lang, true
+				} else if lang != is.Language {
+					is.issuef(file, name, "snippet scope %q doesn't match the import language %q", scope, is.Language)
+				}
+			}
+		}
+
+		content := strings.ReplaceAll(plist["content"], "\r\n", "\n")
+		if textMateShellRegex.MatchString(content) {
+			is.issuef(file, name, "skipped snippet with embedded shell command")
+			continue
+		}
+
+		is.add(file, &SnippetDefinition{
+			Name:     name,
+			Prefixes: []string{trigger},
+			Body:     strings.Split(content, "\n"),
+		})
+	}
+	return is, nil
+}
+
+// textMateScopes returns the scope names in a comma-separated scope selector
+// (only the first scope name of each descendant selector is used, e.g.
+// `source.go string` becomes `source.go`). Empty selectors are ignored.
+func textMateScopes(selector string) []string {
+	var scopes []string
+	for _, s := range strings.Split(selector, ",") {
+		if fields := strings.Fields(s); len(fields) > 0 {
+			scopes = append(scopes, fields[0])
+		}
+	}
+	return scopes
+}
+
+// parsePlistDict parses the top-level string values in a plist dictionary.
+func parsePlistDict(b []byte) (map[string]string, error) {
+	var plist struct {
+		Dict struct {
+			Values []struct {
+				XMLName xml.Name
+				Value   string `xml:",chardata"`
+			} `xml:",any"`
+		} `xml:"dict"`
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(b))
+	decoder.Strict = false
+	if err := decoder.Decode(&plist); err != nil {
+		return nil, err
+	}
+
+	m := map[string]string{}
+	values := plist.Dict.Values
+	for i := 0; i+1 < len(values); i++ {
+		if values[i].XMLName.Local == "key" && values[i+1].XMLName.Local == "string" {
+			m[values[i].Value] = values[i+1].Value
+			i++
+		}
+	}
+	return m, nil
+}
+
+/*********
+ * Files *
+ *********/
+
+// snippetSourceFiles returns the provided file, or all files (with the
+// provided extension, if any) under the provided directory in sorted order.
+func snippetSourceFiles(path, ext string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snippet source: %v", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	if err := filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() && (ext == "" || filepath.Ext(p) == ext) {
+			files = append(files, p)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read snippet directory: %v", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
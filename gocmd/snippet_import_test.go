@@ -0,0 +1,309 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportSnippetSources(t *testing.T) {
+	for _, test := range []struct {
+		format       string
+		wantLanguage string
+		want         []*SnippetDefinition
+		wantIssues   []string
+	}{
+		{
+			format:       "ultisnips",
+			wantLanguage: "go",
+			want: []*SnippetDefinition{
+				{
+					Name:        "Function",
+					Prefixes:    []string{"fn"},
+					Body:        []string{"func ${1:name}(${2}) {", "\t${TM_SELECTED_TEXT}$0", "}"},
+					Description: "Function",
+				},
+				{
+					Name:        "Spaced trigger",
+					Prefixes:    []string{"a b"},
+					Body:        []string{"a `b`"},
+					Description: "Spaced trigger",
+				},
+				{
+					Name:        "Function (2)",
+					Prefixes:    []string{"func"},
+					Body:        []string{"func $1() {}"},
+					Description: "Function",
+				},
+			},
+			wantIssues: []string{
+				`testdata/snippet_import/ultisnips/go.snippets: "priority": ignored unsupported directive on line 2: priority -50`,
+				`testdata/snippet_import/ultisnips/go.snippets: "Function": ignored option 'b' (snippets can't be restricted to the beginning of a line)`,
+				`testdata/snippet_import/ultisnips/go.snippets: "Regex trigger": skipped snippet with regular expression trigger`,
+				`testdata/snippet_import/ultisnips/go.snippets: "Current time": skipped snippet with embedded python, vim, or shell interpolation`,
+				`testdata/snippet_import/ultisnips/go.snippets: "global": python global blocks are not supported`,
+			},
+		},
+		{
+			format:       "yasnippet",
+			wantLanguage: "go",
+			want: []*SnippetDefinition{
+				{
+					Name:     "for loop",
+					Prefixes: []string{"for"},
+					Body:     []string{"for ${1:i} := 0; $1 < ${2:n}; $1++ {", "\t$0", "}"},
+				},
+			},
+			wantIssues: []string{
+				`testdata/snippet_import/yasnippet/go-mode/.yas-parents: ".yas-parents": ignored unsupported yasnippet configuration file`,
+				`testdata/snippet_import/yasnippet/go-mode/cmd: "run command": skipped command snippet (contains elisp)`,
+				`testdata/snippet_import/yasnippet/go-mode/err: "error check": skipped snippet with embedded elisp`,
+				`testdata/snippet_import/yasnippet/go-mode/for: "for loop": ignored unsupported "condition" directive`,
+			},
+		},
+		{
+			format:       "textmate",
+			wantLanguage: "go",
+			want: []*SnippetDefinition{
+				{
+					Name:     "Return",
+					Prefixes: []string{"ret"},
+					Body:     []string{"return $0"},
+				},
+				{
+					Name:     "If",
+					Prefixes: []string{"if"},
+					Body:     []string{"if $1 {", "\t$0", "}"},
+				},
+				{
+					Name:     "Print",
+					Prefixes: []string{"pl"},
+					Body:     []string{"fmt.Println(${1:msg})"},
+				},
+			},
+			wantIssues: []string{
+				`testdata/snippet_import/textmate/key_equivalent.tmSnippet: "Nil": skipped snippet without a tab trigger (key equivalents are not supported)`,
+				`testdata/snippet_import/textmate/multiple_scopes.tmSnippet: "If": snippet scope "source.go string, source.python" has multiple scopes; only the first is used`,
+				`testdata/snippet_import/textmate/shell.tmSnippet: "Date": skipped snippet with embedded shell command`,
+			},
+		},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			is, err := snippetImporters[test.format]("testdata/snippet_import/" + test.format)
+			if err != nil {
+				t.Fatalf("importing %s snippets returned error: %v", test.format, err)
+			}
+
+			if is.Language != test.wantLanguage {
+				t.Errorf("importing %s snippets inferred language %q; want %q", test.format, is.Language, test.wantLanguage)
+			}
+			if got, want := compactJson(is.Snippets), compactJson(test.want); got != want {
+				t.Errorf("importing %s snippets returned:\n%s\nwant:\n%s", test.format, got, want)
+			}
+			if got, want := strings.Join(is.Issues, "\n"), strings.Join(test.wantIssues, "\n"); got != want {
+				t.Errorf("importing %s snippets returned issues:\n%s\nwant:\n%s", test.format, got, want)
+			}
+		})
+	}
+}
+
+func TestImportSnippetLanguages(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		format       string
+		path         string
+		wantLanguage string
+		// wantScopes maps snippet names to their scope.
+		wantScopes map[string]string
+		wantIssues []string
+	}{
+		{
+			name:       "ultisnips global file",
+			format:     "ultisnips",
+			path:       "ultisnips_mixed/all.snippets",
+			wantScopes: map[string]string{"TODO comment": ""},
+		},
+		{
+			name:         "ultisnips single file",
+			format:       "ultisnips",
+			path:         "ultisnips_mixed/go.snippets",
+			wantLanguage: "go",
+			wantScopes:   map[string]string{"Function": ""},
+		},
+		{
+			name:       "ultisnips mixed filetypes",
+			format:     "ultisnips",
+			path:       "ultisnips_mixed",
+			wantScopes: map[string]string{"TODO comment": "", "Function": "go", "Class": "python"},
+			wantIssues: []string{
+				"testdata/snippet_import/ultisnips_mixed: source files have multiple languages [global go python]; snippets are imported as global snippets scoped to their source file's language",
+			},
+		},
+		{
+			name:       "yasnippet global mode",
+			format:     "yasnippet",
+			path:       "yasnippet_mixed/fundamental-mode",
+			wantScopes: map[string]string{"todo": ""},
+		},
+		{
+			name:       "yasnippet mixed modes",
+			format:     "yasnippet",
+			path:       "yasnippet_mixed",
+			wantScopes: map[string]string{"todo": "", "function": "python"},
+			wantIssues: []string{
+				"testdata/snippet_import/yasnippet_mixed: source files have multiple languages [global python]; snippets are imported as global snippets scoped to their source file's language",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			is, err := snippetImporters[test.format]("testdata/snippet_import/" + test.path)
+			if err != nil {
+				t.Fatalf("importing %s snippets returned error: %v", test.format, err)
+			}
+
+			if !is.Inferred || is.Language != test.wantLanguage {
+				t.Errorf("importing %s snippets inferred language (%q, %v); want (%q, true)", test.format, is.Language, is.Inferred, test.wantLanguage)
+			}
+			scopes := map[string]string{}
+			for _, s := range is.Snippets {
+				scopes[s.Name] = s.Scope
+			}
+			if got, want := compactJson(scopes), compactJson(test.wantScopes); got != want {
+				t.Errorf("importing %s snippets returned scopes %s; want %s", test.format, got, want)
+			}
+			if got, want := strings.Join(is.Issues, "\n"), strings.Join(test.wantIssues, "\n"); got != want {
+				t.Errorf("importing %s snippets returned issues:\n%s\nwant:\n%s", test.format, got, want)
+			}
+		})
+	}
+}
+
+func TestImportSnippets(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		format    string
+		path      string
+		language  string
+		want      string
+		wantScope string
+		wantErr   string
+	}{
+		{
+			name:   "infers language",
+			format: "textmate",
+			want:   "snippets/textmate-go.json",
+		},
+		{
+			name:     "explicit language",
+			format:   "yasnippet",
+			language: "golang",
+			want:     "snippets/yasnippet-golang.json",
+		},
+		{
+			name:   "global snippets",
+			format: "ultisnips",
+			path:   "ultisnips_mixed/all.snippets",
+			want:   "snippets/ultisnips-global.json",
+		},
+		{
+			name:      "mixed languages are global",
+			format:    "ultisnips",
+			path:      "ultisnips_mixed",
+			want:      "snippets/ultisnips-global.json",
+			wantScope: "go",
+		},
+		{
+			name:     "explicit language ignores scopes",
+			format:   "ultisnips",
+			path:     "ultisnips_mixed",
+			language: "go",
+			want:     "snippets/ultisnips-go.json",
+		},
+		{
+			name:    "unknown format",
+			format:  "snipmate",
+			wantErr: `unknown snippet format "snipmate" (expected one of [textmate ultisnips yasnippet])`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			path := test.path
+			if path == "" {
+				path = test.format
+			}
+			sf, _, err := importSnippets(test.format, "testdata/snippet_import/"+path, test.language, "")
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("importSnippets() returned error %v; want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("importSnippets() returned error: %v", err)
+			}
+			if sf.Path != test.want {
+				t.Errorf("importSnippets() returned path %q; want %q", sf.Path, test.want)
+			}
+			for _, s := range sf.Snippets {
+				if s.Name == "Function" && s.Scope != test.wantScope {
+					t.Errorf("importSnippets() returned scope %q for %q; want %q", s.Scope, s.Name, test.wantScope)
+				}
+			}
+		})
+	}
+}
+
+func TestParseUltiSnipsHeader(t *testing.T) {
+	for _, test := range []struct {
+		header          string
+		wantTrigger     string
+		wantDescription string
+		wantOptions     string
+	}{
+		{`fn`, "fn", "", ""},
+		{`fn "Function"`, "fn", "Function", ""},
+		{`fn "Function" b`, "fn", "Function", "b"},
+		{`!a b! "Spaced"`, "a b", "Spaced", ""},
+		{`"re(g|x)" "Regex" r`, "re(g|x)", "Regex", "r"},
+	} {
+		t.Run(test.header, func(t *testing.T) {
+			trigger, description, options := parseUltiSnipsHeader(test.header)
+			if trigger != test.wantTrigger || description != test.wantDescription || options != test.wantOptions {
+				t.Errorf("parseUltiSnipsHeader(%q) returned (%q, %q, %q); want (%q, %q, %q)", test.header, trigger, description, options, test.wantTrigger, test.wantDescription, test.wantOptions)
+			}
+		})
+	}
+}
+
+func TestSnippetLanguageID(t *testing.T) {
+	for _, test := range []struct {
+		source string
+		want   string
+	}{
+		{"go", "go"},
+		{"python-mode", "python"},
+		{"typescript-ts-mode", "typescript"},
+		{"source.js", "javascript"},
+		{"text.tex.latex", "latex"},
+		{"sh", "shellscript"},
+		{"all", ""},
+	} {
+		if got := snippetLanguageID(test.source); got != test.want {
+			t.Errorf("snippetLanguageID(%q) returned %q; want %q", test.source, got, test.want)
+		}
+	}
+}
+
+func TestTextMateScopes(t *testing.T) {
+	for _, test := range []struct {
+		selector string
+		want     []string
+	}{
+		{"source.go", []string{"source.go"}},
+		{", source.go", []string{"source.go"}},
+		{"source.go string, source.python", []string{"source.go", "source.python"}},
+		{" , ", nil},
+	} {
+		if got := textMateScopes(test.selector); strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("textMateScopes(%q) returned %q; want %q", test.selector, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	// See the following link for the list of supported variables:
+	// https://code.visualstudio.com/docs/editor/userdefinedsnippets#_variables
+	knownSnippetVariables = []string{
+		"TM_SELECTED_TEXT",
+		"TM_CURRENT_LINE",
+		"TM_CURRENT_WORD",
+		"TM_LINE_INDEX",
+		"TM_LINE_NUMBER",
+		"TM_FILENAME",
+		"TM_FILENAME_BASE",
+		"TM_DIRECTORY",
+		"TM_FILEPATH",
+		"RELATIVE_FILEPATH",
+		"CLIPBOARD",
+		"WORKSPACE_NAME",
+		"WORKSPACE_FOLDER",
+		"CURSOR_INDEX",
+		"CURSOR_NUMBER",
+		"CURRENT_YEAR",
+		"CURRENT_YEAR_SHORT",
+		"CURRENT_MONTH",
+		"CURRENT_MONTH_NAME",
+		"CURRENT_MONTH_NAME_SHORT",
+		"CURRENT_DATE",
+		"CURRENT_DAY_NAME",
+		"CURRENT_DAY_NAME_SHORT",
+		"CURRENT_HOUR",
+		"CURRENT_MINUTE",
+		"CURRENT_SECOND",
+		"CURRENT_SECONDS_UNIX",
+		"CURRENT_TIMEZONE_OFFSET",
+		"RANDOM",
+		"RANDOM_HEX",
+		"UUID",
+		"BLOCK_COMMENT_START",
+		"BLOCK_COMMENT_END",
+		"LINE_COMMENT",
+	}
+
+	// Javascript regex flags
+	snippetRegexOptions = "dgimsuvy"
+)
+
+// lintSnippet returns a list of issues with the provided snippet.
+func lintSnippet(s *SnippetDefinition) []string {
+	elements, err := parseSnippet(s.Body)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to parse body: %v", err)}
+	}
+
+	var issues []string
+	defaults := map[int]map[string]bool{}
+	tabstops := map[int]bool{}
+	addDefault := func(n int, v string) {
+		if defaults[n] == nil {
+			defaults[n] = map[string]bool{}
+		}
+		defaults[n][v] = true
+	}
+
+	walkSnippet(elements, func(e SnippetElement) {
+		switch e := e.(type) {
+		case *SnippetTabstop:
+			tabstops[e.Index] = true
+			issues = append(issues, lintSnippetTransform(fmt.Sprintf("tabstop %d", e.Index), e.Transform)...)
+		case *SnippetPlaceholder:
+			tabstops[e.Index] = true
+			addDefault(e.Index, snippetElementsString(e.Default, true))
+		case *SnippetChoice:
+			tabstops[e.Index] = true
+			addDefault(e.Index, fmt.Sprintf("choice %q", e.Options))
+		case *SnippetVariable:
+			if !slices.Contains(knownSnippetVariables, e.Name) {
+				issues = append(issues, fmt.Sprintf("unknown variable %q", e.Name))
+			}
+			issues = append(issues, lintSnippetTransform(fmt.Sprintf("variable %s", e.Name), e.Transform)...)
+		}
+	})
+
+	// Inconsistent defaults
+	indexes := maps.Keys(defaults)
+	slices.Sort(indexes)
+	for _, n := range indexes {
+		if len(defaults[n]) > 1 {
+			values := maps.Keys(defaults[n])
+			slices.Sort(values)
+			issues = append(issues, fmt.Sprintf("tabstop %d has inconsistent default values: %q", n, values))
+		}
+	}
+
+	// Tabstop numbering ($0 is the final cursor position, so it isn't part of the sequence).
+	var nums []int
+	for n := range tabstops {
+		if n != 0 {
+			nums = append(nums, n)
+		}
+	}
+	slices.Sort(nums)
+	for i, n := range nums {
+		if n != i+1 {
+			issues = append(issues, fmt.Sprintf("tabstops must be numbered sequentially starting at 1; got %v", nums))
+			break
+		}
+	}
+	return issues
+}
+
+func lintSnippetTransform(name string, t *SnippetTransform) []string {
+	if t == nil {
+		return nil
+	}
+
+	var issues []string
+	if _, err := syntax.Parse(t.Regex, syntax.Perl); err != nil {
+		// Go regexes don't support some javascript features (e.g. lookarounds and
+		// backreferences), so we can't say those are invalid.
+		if se, ok := err.(*syntax.Error); !ok || !unsupportedJavascriptRegex(se) {
+			issues = append(issues, fmt.Sprintf("%s has an invalid regex: %v", name, err))
+		}
+	}
+
+	for i, o := range t.Options {
+		if !strings.ContainsRune(snippetRegexOptions, o) {
+			issues = append(issues, fmt.Sprintf("%s has an invalid regex option %q (expected one of %q)", name, o, snippetRegexOptions))
+		} else if strings.ContainsRune(t.Options[:i], o) {
+			issues = append(issues, fmt.Sprintf("%s has a duplicate regex option %q", name, o))
+		}
+	}
+	return issues
+}
+
+// unsupportedJavascriptRegex returns whether the regex error is caused by a
+// javascript regex feature that Go doesn't support.
+func unsupportedJavascriptRegex(err *syntax.Error) bool {
+	switch err.Code {
+	case syntax.ErrInvalidPerlOp, syntax.ErrInvalidEscape:
+		return true
+	case syntax.ErrInvalidNamedCapture:
+		// Lookbehinds (`(?<=...)` and `(?<!...)`) look like named captures.
+		return strings.HasPrefix(err.Expr, "(?<=") || strings.HasPrefix(err.Expr, "(?<!")
+	}
+	return false
+}
+
+// walkSnippet calls f for every element (including nested elements).
+func walkSnippet(elements []SnippetElement, f func(SnippetElement)) {
+	for _, e := range elements {
+		f(e)
+		switch e := e.(type) {
+		case *SnippetPlaceholder:
+			walkSnippet(e.Default, f)
+		case *SnippetVariable:
+			walkSnippet(e.Default, f)
+		}
+	}
+}
+
+// lintSnippetFiles lints the provided snippet files and returns a list of
+// issues.
+func lintSnippetFiles(files []*SnippetFile) []string {
+	type prefixUse struct {
+		file      string
+		name      string
+		languages []string
+	}
+	prefixes := map[string][]*prefixUse{}
+
+	var issues []string
+	for _, sf := range files {
+		for _, def := range sf.Snippets {
+			for _, issue := range lintSnippet(def) {
+				issues = append(issues, fmt.Sprintf("%s: %q: %s", sf.Path, def.Name, issue))
+			}
+
+			languages := []string{sf.Language}
+			if def.Scope != "" {
+				languages = strings.Split(def.Scope, ",")
+			}
+			for _, prefix := range def.Prefixes {
+				prefixes[prefix] = append(prefixes[prefix], &prefixUse{sf.Path, def.Name, languages})
+			}
+		}
+	}
+
+	// Prefix collisions
+	prefixKeys := maps.Keys(prefixes)
+	sort.Strings(prefixKeys)
+	for _, prefix := range prefixKeys {
+		uses := prefixes[prefix]
+		for i, a := range uses {
+			for _, b := range uses[i+1:] {
+				if langs := overlappingLanguages(a.languages, b.languages); len(langs) > 0 {
+					issues = append(issues, fmt.Sprintf("prefix %q is used by both %s: %q and %s: %q (languages: %v)", prefix, a.file, a.name, b.file, b.name, langs))
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// overlappingLanguages returns the languages that are in both lists (an empty
+// language indicates a global snippet that applies to all languages).
+func overlappingLanguages(a, b []string) []string {
+	var r []string
+	for _, al := range a {
+		for _, bl := range b {
+			al, bl = strings.TrimSpace(al), strings.TrimSpace(bl)
+			switch {
+			case al == bl:
+				r = append(r, al)
+			case al == "":
+				r = append(r, bl)
+			case bl == "":
+				r = append(r, al)
+			}
+		}
+	}
+	return r
+}
+
+// stringOrList unmarshals either a json string or a list of json strings.
+type stringOrList []string
+
+func (sl *stringOrList) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*sl = []string{s}
+		return nil
+	}
+
+	var l []string
+	if err := json.Unmarshal(b, &l); err != nil {
+		return fmt.Errorf("expected string or list of strings: %v", err)
+	}
+	*sl = l
+	return nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintSnippet(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		body []string
+		want []string
+	}{
+		{
+			name: "valid snippet",
+			body: []string{"func ${1:name}($2) {", "\t$0", "}"},
+		},
+		{
+			name: "unclosed placeholder",
+			body: []string{"${1:abc"},
+			want: []string{"failed to parse body: 1:1: unclosed placeholder for tabstop 1"},
+		},
+		{
+			name: "unclosed transform",
+			body: []string{"${1/(.*)/abc"},
+			want: []string{"failed to parse body: 1:1: unclosed transform format"},
+		},
+		{
+			name: "known variables",
+			body: []string{"$TM_FILENAME ${CLIPBOARD:clip} ${UUID/-//g}"},
+		},
+		{
+			name: "unknown variable",
+			body: []string{"$TM_FILENAM ${NOPE:default}"},
+			want: []string{
+				`unknown variable "TM_FILENAM"`,
+				`unknown variable "NOPE"`,
+			},
+		},
+		{
+			name: "invalid regex",
+			body: []string{"${1:a} ${1/(abc/x/}"},
+			want: []string{"tabstop 1 has an invalid regex: error parsing regexp: missing closing ): `(abc`"},
+		},
+		{
+			name: "javascript-only regex features are allowed",
+			body: []string{`${1:a} ${1/(?<=a)b/x/} ${1/(?<!a)b/x/} ${1/(a)\k<name>/y/} ${TM_FILENAME/(?!test)\.go/z/}`},
+		},
+		{
+			name: "invalid named capture",
+			body: []string{"${1:a} ${1/(?P<a-b>c)/x/}"},
+			want: []string{"tabstop 1 has an invalid regex: error parsing regexp: invalid named capture: `(?P<a-b>`"},
+		},
+		{
+			name: "invalid regex option",
+			body: []string{"${1:a} ${1/a/b/gx}"},
+			want: []string{`tabstop 1 has an invalid regex option 'x' (expected one of "dgimsuvy")`},
+		},
+		{
+			name: "duplicate regex option",
+			body: []string{"${TM_FILENAME/a/b/gig}"},
+			want: []string{`variable TM_FILENAME has a duplicate regex option 'g'`},
+		},
+		{
+			name: "consistent defaults",
+			body: []string{"${1:a} ${1:a} $1"},
+		},
+		{
+			name: "inconsistent defaults",
+			body: []string{"${1:a} ${1:b} ${2|x,y|} ${2:x}"},
+			want: []string{
+				`tabstop 1 has inconsistent default values: ["a" "b"]`,
+				`tabstop 2 has inconsistent default values: ["choice [\"x\" \"y\"]" "x"]`,
+			},
+		},
+		{
+			name: "nested tabstops count towards numbering",
+			body: []string{"${1:a ${2:b}} $0"},
+		},
+		{
+			name: "tabstops not starting at 1",
+			body: []string{"$2 $3 $0"},
+			want: []string{"tabstops must be numbered sequentially starting at 1; got [2 3]"},
+		},
+		{
+			name: "tabstops with a gap",
+			body: []string{"$1 ${3:c}"},
+			want: []string{"tabstops must be numbered sequentially starting at 1; got [1 3]"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := lintSnippet(&SnippetDefinition{Name: "test", Body: test.body})
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("lintSnippet() returned:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestLintSnippetFiles(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		files []*SnippetFile
+		want  []string
+	}{
+		{
+			name: "no files",
+		},
+		{
+			name: "reports snippet issues",
+			files: []*SnippetFile{
+				{
+					Path:     "snippets/go.json",
+					Language: "go",
+					Snippets: []*SnippetDefinition{
+						{Name: "ok", Prefixes: []string{"ok"}, Body: []string{"$1"}},
+						{Name: "bad", Prefixes: []string{"bad"}, Body: []string{"$2"}},
+					},
+				},
+			},
+			want: []string{
+				`snippets/go.json: "bad": tabstops must be numbered sequentially starting at 1; got [2]`,
+			},
+		},
+		{
+			name: "same prefix in different languages",
+			files: []*SnippetFile{
+				{Path: "go.json", Language: "go", Snippets: []*SnippetDefinition{{Name: "go func", Prefixes: []string{"fn"}}}},
+				{Path: "java.json", Language: "java", Snippets: []*SnippetDefinition{{Name: "java func", Prefixes: []string{"fn"}}}},
+			},
+		},
+		{
+			name: "same prefix in the same language",
+			files: []*SnippetFile{
+				{Path: "go.json", Language: "go", Snippets: []*SnippetDefinition{
+					{Name: "one", Prefixes: []string{"fn", "f"}},
+					{Name: "two", Prefixes: []string{"fn"}},
+				}},
+			},
+			want: []string{
+				`prefix "fn" is used by both go.json: "one" and go.json: "two" (languages: [go])`,
+			},
+		},
+		{
+			name: "global snippet collides with every language",
+			files: []*SnippetFile{
+				{Path: "global.json", Snippets: []*SnippetDefinition{{Name: "global", Prefixes: []string{"fn"}}}},
+				{Path: "go.json", Language: "go", Snippets: []*SnippetDefinition{{Name: "go", Prefixes: []string{"fn"}}}},
+				{Path: "java.json", Language: "java", Snippets: []*SnippetDefinition{{Name: "java", Prefixes: []string{"fn"}}}},
+			},
+			want: []string{
+				`prefix "fn" is used by both global.json: "global" and go.json: "go" (languages: [go])`,
+				`prefix "fn" is used by both global.json: "global" and java.json: "java" (languages: [java])`,
+			},
+		},
+		{
+			name: "scoped global snippet",
+			files: []*SnippetFile{
+				{Path: "global.json", Snippets: []*SnippetDefinition{{Name: "scoped", Prefixes: []string{"fn"}, Scope: "python, java"}}},
+				{Path: "go.json", Language: "go", Snippets: []*SnippetDefinition{{Name: "go", Prefixes: []string{"fn"}}}},
+				{Path: "java.json", Language: "java", Snippets: []*SnippetDefinition{{Name: "java", Prefixes: []string{"fn"}}}},
+			},
+			want: []string{
+				`prefix "fn" is used by both global.json: "scoped" and java.json: "java" (languages: [java])`,
+			},
+		},
+		{
+			name:  "contributed snippets",
+			files: SnippetFiles,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := lintSnippetFiles(test.files)
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("lintSnippetFiles() returned:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestOverlappingLanguages(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		a    []string
+		b    []string
+		want []string
+	}{
+		{
+			name: "no languages",
+		},
+		{
+			name: "disjoint languages",
+			a:    []string{"go"},
+			b:    []string{"java", "python"},
+		},
+		{
+			name: "shared language",
+			a:    []string{"go", " java"},
+			b:    []string{"java ", "python"},
+			want: []string{"java"},
+		},
+		{
+			name: "global and language",
+			a:    []string{""},
+			b:    []string{"go", "java"},
+			want: []string{"go", "java"},
+		},
+		{
+			name: "language and global",
+			a:    []string{"python"},
+			b:    []string{""},
+			want: []string{"python"},
+		},
+		{
+			name: "both global",
+			a:    []string{""},
+			b:    []string{""},
+			want: []string{""},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := overlappingLanguages(test.a, test.b)
+			if strings.Join(got, ",") != strings.Join(test.want, ",") || len(got) != len(test.want) {
+				t.Errorf("overlappingLanguages(%q, %q) returned %q; want %q", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
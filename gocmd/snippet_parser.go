@@ -0,0 +1,588 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// This file contains a parser for the VS Code (TextMate) snippet grammar:
+// https://code.visualstudio.com/docs/editor/userdefinedsnippets#_grammar
+
+// SnippetElement is a single node in a parsed snippet body.
+type SnippetElement interface {
+	// snippetString returns the element in snippet syntax. nested indicates
+	// whether the element is inside of a placeholder or variable default.
+	snippetString(nested bool) string
+}
+
+type SnippetText struct {
+	Value string
+}
+
+// SnippetTabstop is a `$1`, `${1}`, or `${1/regex/format/options}` element.
+type SnippetTabstop struct {
+	Index     int
+	Transform *SnippetTransform
+}
+
+// SnippetPlaceholder is a `${1:default}` element.
+type SnippetPlaceholder struct {
+	Index   int
+	Default []SnippetElement
+}
+
+// SnippetChoice is a `${1|one,two,three|}` element.
+type SnippetChoice struct {
+	Index   int
+	Options []string
+}
+
+// SnippetVariable is a `$NAME`, `${NAME}`, `${NAME:default}`, or
+// `${NAME/regex/format/options}` element.
+type SnippetVariable struct {
+	Name       string
+	Default    []SnippetElement
+	HasDefault bool
+	Transform  *SnippetTransform
+}
+
+type SnippetTransform struct {
+	Regex   string
+	Format  []*SnippetFormat
+	Options string
+}
+
+// SnippetFormat is either literal text (if Index is nil) or a reference to a
+// regex capture group.
+type SnippetFormat struct {
+	Text  string
+	Index *int
+	// Modifier is one of the supported case modifiers (e.g. `upcase`).
+	Modifier string
+	// If and Else are used for conditional insertion (`${1:+if}`,
+	// `${1:?if:else}`, `${1:-else}`, and `${1:else}`).
+	If      string
+	Else    string
+	HasIf   bool
+	HasElse bool
+}
+
+var (
+	snippetFormatModifiers = []string{
+		"upcase",
+		"downcase",
+		"capitalize",
+		"camelcase",
+		"pascalcase",
+	}
+)
+
+// SnippetParseError is returned when a snippet body is not valid.
+type SnippetParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *SnippetParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// parseSnippet parses the provided snippet body lines.
+func parseSnippet(body []string) ([]SnippetElement, error) {
+	p := &snippetParser{text: []rune(strings.Join(body, "\n"))}
+	elements, err := p.parseAny(nil)
+	if err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+type snippetParser struct {
+	text []rune
+	pos  int
+}
+
+func (p *snippetParser) errorf(pos int, format string, a ...interface{}) error {
+	line, col := 1, 1
+	for _, r := range p.text[:pos] {
+		if r == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return &SnippetParseError{line, col, fmt.Sprintf(format, a...)}
+}
+
+func (p *snippetParser) done() bool {
+	return p.pos >= len(p.text)
+}
+
+func (p *snippetParser) peek(offset int) (rune, bool) {
+	if p.pos+offset >= len(p.text) {
+		return 0, false
+	}
+	return p.text[p.pos+offset], true
+}
+
+func (p *snippetParser) accept(r rune) bool {
+	if c, ok := p.peek(0); ok && c == r {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// parseAny parses elements until the end of the text or until one of the
+// provided (unescaped) terminators is reached. The terminator is not consumed.
+func (p *snippetParser) parseAny(terminators []rune) ([]SnippetElement, error) {
+	var elements []SnippetElement
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			elements = append(elements, &SnippetText{text.String()})
+			text.Reset()
+		}
+	}
+
+	for !p.done() {
+		c, _ := p.peek(0)
+		if slices.Contains(terminators, c) {
+			break
+		}
+
+		switch c {
+		case '\\':
+			// Only `$`, `}`, and `\` can be escaped (anything else is literal text).
+			if n, ok := p.peek(1); ok && (n == '$' || n == '}' || n == '\\') {
+				text.WriteRune(n)
+				p.pos += 2
+				continue
+			}
+		case '$':
+			e, err := p.parseDollar()
+			if err != nil {
+				return nil, err
+			}
+			if e != nil {
+				flush()
+				elements = append(elements, e)
+				continue
+			}
+		}
+		text.WriteRune(c)
+		p.pos++
+	}
+	flush()
+	return elements, nil
+}
+
+// parseDollar parses an element that begins with `$`. If the dollar sign
+// doesn't begin an element, then nil is returned and the position is unchanged.
+func (p *snippetParser) parseDollar() (SnippetElement, error) {
+	start := p.pos
+	p.pos++ // $
+
+	// $1
+	if n, ok := p.parseInt(); ok {
+		return &SnippetTabstop{Index: n}, nil
+	}
+
+	// $VAR
+	if name, ok := p.parseVarName(); ok {
+		return &SnippetVariable{Name: name}, nil
+	}
+
+	if !p.accept('{') {
+		// Just a regular dollar sign
+		p.pos = start
+		return nil, nil
+	}
+
+	if n, ok := p.parseInt(); ok {
+		return p.parseIndexedBlock(start, n)
+	}
+
+	if name, ok := p.parseVarName(); ok {
+		return p.parseVariableBlock(start, name)
+	}
+
+	return nil, p.errorf(start, "`${` must be followed by a tabstop number or variable name")
+}
+
+// parseIndexedBlock parses the rest of a `${1...}` element.
+func (p *snippetParser) parseIndexedBlock(start, n int) (SnippetElement, error) {
+	switch {
+	case p.accept('}'):
+		return &SnippetTabstop{Index: n}, nil
+	case p.accept(':'):
+		def, err := p.parseAny([]rune{'}'})
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept('}') {
+			return nil, p.errorf(start, "unclosed placeholder for tabstop %d", n)
+		}
+		return &SnippetPlaceholder{n, def}, nil
+	case p.accept('|'):
+		options, err := p.parseChoiceOptions(start)
+		if err != nil {
+			return nil, err
+		}
+		return &SnippetChoice{n, options}, nil
+	case p.accept('/'):
+		t, err := p.parseTransform(start)
+		if err != nil {
+			return nil, err
+		}
+		return &SnippetTabstop{n, t}, nil
+	}
+	return nil, p.errorf(start, "unclosed `${` for tabstop %d", n)
+}
+
+// parseVariableBlock parses the rest of a `${VAR...}` element.
+func (p *snippetParser) parseVariableBlock(start int, name string) (SnippetElement, error) {
+	switch {
+	case p.accept('}'):
+		return &SnippetVariable{Name: name}, nil
+	case p.accept(':'):
+		def, err := p.parseAny([]rune{'}'})
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept('}') {
+			return nil, p.errorf(start, "unclosed default value for variable %s", name)
+		}
+		return &SnippetVariable{Name: name, Default: def, HasDefault: true}, nil
+	case p.accept('/'):
+		t, err := p.parseTransform(start)
+		if err != nil {
+			return nil, err
+		}
+		return &SnippetVariable{Name: name, Transform: t}, nil
+	}
+	return nil, p.errorf(start, "unclosed `${` for variable %s", name)
+}
+
+func (p *snippetParser) parseChoiceOptions(start int) ([]string, error) {
+	var options []string
+	var option strings.Builder
+	for !p.done() {
+		c, _ := p.peek(0)
+		switch c {
+		case '\\':
+			if n, ok := p.peek(1); ok && (n == ',' || n == '|' || n == '\\' || n == '$' || n == '}') {
+				option.WriteRune(n)
+				p.pos += 2
+				continue
+			}
+		case ',':
+			options = append(options, option.String())
+			option.Reset()
+			p.pos++
+			continue
+		case '|':
+			if n, ok := p.peek(1); ok && n == '}' {
+				p.pos += 2
+				return append(options, option.String()), nil
+			}
+		}
+		option.WriteRune(c)
+		p.pos++
+	}
+	return nil, p.errorf(start, "unclosed choice (expected `|}`)")
+}
+
+// parseTransform parses the rest of a `/regex/format/options}` transform
+// (the leading slash is already consumed).
+func (p *snippetParser) parseTransform(start int) (*SnippetTransform, error) {
+	t := &SnippetTransform{}
+
+	// Regex
+	var regex strings.Builder
+	for {
+		c, ok := p.peek(0)
+		if !ok {
+			return nil, p.errorf(start, "unclosed transform regex")
+		}
+		p.pos++
+		if c == '/' {
+			break
+		}
+		if c == '\\' {
+			if n, ok := p.peek(0); ok && n == '/' {
+				regex.WriteRune('/')
+				p.pos++
+				continue
+			}
+		}
+		regex.WriteRune(c)
+	}
+	t.Regex = regex.String()
+
+	// Format
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			t.Format = append(t.Format, &SnippetFormat{Text: text.String()})
+			text.Reset()
+		}
+	}
+	for {
+		c, ok := p.peek(0)
+		if !ok {
+			return nil, p.errorf(start, "unclosed transform format")
+		}
+		if c == '/' {
+			p.pos++
+			break
+		}
+		if c == '\\' {
+			if n, ok := p.peek(1); ok && (n == '/' || n == '$' || n == '\\' || n == '}') {
+				text.WriteRune(n)
+				p.pos += 2
+				continue
+			}
+		}
+		if c == '$' {
+			f, err := p.parseFormat()
+			if err != nil {
+				return nil, err
+			}
+			if f != nil {
+				flush()
+				t.Format = append(t.Format, f)
+				continue
+			}
+		}
+		text.WriteRune(c)
+		p.pos++
+	}
+	flush()
+
+	// Options
+	var options strings.Builder
+	for {
+		c, ok := p.peek(0)
+		if !ok {
+			return nil, p.errorf(start, "unclosed transform (expected `}` after options)")
+		}
+		p.pos++
+		if c == '}' {
+			break
+		}
+		options.WriteRune(c)
+	}
+	t.Options = options.String()
+	return t, nil
+}
+
+// parseFormat parses a format element (`$1`, `${1}`, `${1:/upcase}`, etc.)
+// If the dollar sign doesn't begin a format element, then nil is returned and
+// the position is unchanged.
+func (p *snippetParser) parseFormat() (*SnippetFormat, error) {
+	start := p.pos
+	p.pos++ // $
+
+	if n, ok := p.parseInt(); ok {
+		return &SnippetFormat{Index: &n}, nil
+	}
+
+	if !p.accept('{') {
+		p.pos = start
+		return nil, nil
+	}
+
+	n, ok := p.parseInt()
+	if !ok {
+		return nil, p.errorf(start, "format `${` must be followed by a capture group number")
+	}
+	f := &SnippetFormat{Index: &n}
+
+	if p.accept('}') {
+		return f, nil
+	}
+	if !p.accept(':') {
+		return nil, p.errorf(start, "unclosed format `${` for capture group %d", n)
+	}
+
+	switch {
+	case p.accept('/'):
+		modifier := p.readUntil('}')
+		if !slices.Contains(snippetFormatModifiers, modifier) {
+			return nil, p.errorf(start, "unknown format modifier %q (expected one of %v)", modifier, snippetFormatModifiers)
+		}
+		f.Modifier = modifier
+	case p.accept('+'):
+		f.If, f.HasIf = p.readUntil('}'), true
+	case p.accept('?'):
+		f.If, f.HasIf = p.readUntil(':'), true
+		if !p.accept(':') {
+			return nil, p.errorf(start, "conditional format for capture group %d is missing `:else` section", n)
+		}
+		f.Else, f.HasElse = p.readUntil('}'), true
+	case p.accept('-'):
+		f.Else, f.HasElse = p.readUntil('}'), true
+	default:
+		f.Else, f.HasElse = p.readUntil('}'), true
+	}
+
+	if !p.accept('}') {
+		return nil, p.errorf(start, "unclosed format `${` for capture group %d", n)
+	}
+	return f, nil
+}
+
+// readUntil reads (and unescapes) text until the provided (unescaped) rune.
+// The terminating rune is not consumed.
+func (p *snippetParser) readUntil(end rune) string {
+	var sb strings.Builder
+	for !p.done() {
+		c, _ := p.peek(0)
+		if c == end {
+			break
+		}
+		if c == '\\' {
+			if n, ok := p.peek(1); ok {
+				sb.WriteRune(n)
+				p.pos += 2
+				continue
+			}
+		}
+		sb.WriteRune(c)
+		p.pos++
+	}
+	return sb.String()
+}
+
+func (p *snippetParser) parseInt() (int, bool) {
+	start := p.pos
+	for {
+		c, ok := p.peek(0)
+		if !ok || c < '0' || c > '9' {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false
+	}
+	n, err := strconv.Atoi(string(p.text[start:p.pos]))
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+	return n, true
+}
+
+func (p *snippetParser) parseVarName() (string, bool) {
+	start := p.pos
+	for {
+		c, ok := p.peek(0)
+		if !ok {
+			break
+		}
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(isDigit && p.pos > start) {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", false
+	}
+	return string(p.text[start:p.pos]), true
+}
+
+/**************************
+ * Snippet syntax strings *
+ **************************/
+
+var (
+	snippetTextEscaper       = strings.NewReplacer(`\`, `\\`, `$`, `\$`)
+	snippetNestedTextEscaper = strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`)
+	snippetFormatEscaper     = strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`, `/`, `\/`)
+)
+
+// snippetElementsString returns the elements in snippet syntax.
+func snippetElementsString(elements []SnippetElement, nested bool) string {
+	var sb strings.Builder
+	for i, e := range elements {
+		s := e.snippetString(nested)
+		// Use the braced form if the tabstop is followed by a digit (`${1}2` rather than `$12`).
+		if t, ok := e.(*SnippetTabstop); ok && t.Transform == nil && i+1 < len(elements) {
+			if next := elements[i+1].snippetString(nested); next != "" && next[0] >= '0' && next[0] <= '9' {
+				s = fmt.Sprintf("${%d}", t.Index)
+			}
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+func (t *SnippetText) snippetString(nested bool) string {
+	if nested {
+		return snippetNestedTextEscaper.Replace(t.Value)
+	}
+	return snippetTextEscaper.Replace(t.Value)
+}
+
+func (t *SnippetTabstop) snippetString(bool) string {
+	if t.Transform != nil {
+		regex, format, options := t.Transform.parts()
+		return snipTransform(t.Index, regex, format, options)
+	}
+	return snipTabstop(t.Index)
+}
+
+func (p *SnippetPlaceholder) snippetString(bool) string {
+	return snipPlaceholder(p.Index, snippetElementsString(p.Default, true))
+}
+
+func (c *SnippetChoice) snippetString(bool) string {
+	return snipChoice(c.Index, c.Options...)
+}
+
+func (v *SnippetVariable) snippetString(bool) string {
+	switch {
+	case v.Transform != nil:
+		regex, format, options := v.Transform.parts()
+		return snipVariableTransform(v.Name, regex, format, options)
+	case v.HasDefault:
+		return snipVariableDefault(v.Name, snippetElementsString(v.Default, true))
+	}
+	return snipVariable(v.Name)
+}
+
+// parts returns the regex, format, and options of the transform in snippet
+// syntax.
+func (t *SnippetTransform) parts() (string, string, string) {
+	var format strings.Builder
+	for _, f := range t.Format {
+		format.WriteString(f.snippetString())
+	}
+	return strings.ReplaceAll(t.Regex, "/", `\/`), format.String(), t.Options
+}
+
+func (f *SnippetFormat) snippetString() string {
+	if f.Index == nil {
+		return snippetFormatEscaper.Replace(f.Text)
+	}
+	switch {
+	case f.Modifier != "":
+		return fmt.Sprintf("${%d:/%s}", *f.Index, f.Modifier)
+	case f.HasIf && f.HasElse:
+		return fmt.Sprintf("${%d:?%s:%s}", *f.Index, f.If, f.Else)
+	case f.HasIf:
+		return fmt.Sprintf("${%d:+%s}", *f.Index, f.If)
+	case f.HasElse:
+		return fmt.Sprintf("${%d:-%s}", *f.Index, f.Else)
+	}
+	return fmt.Sprintf("${%d}", *f.Index)
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/exp/slices"
+)
+
+// This file contains an offline snippet expander so snippets can be rendered
+// (and tested) without running VS Code.
+
+// findSnippet returns the snippet definition with the provided name.
+func findSnippet(name string) (*SnippetDefinition, error) {
+	for _, sf := range SnippetFiles {
+		for _, s := range sf.Snippets {
+			if s.Name == name {
+				return s, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown snippet %q", name)
+}
+
+// parseSnippetValues converts a list of `key=value` strings into a map of
+// snippet values (see renderSnippet).
+func parseSnippetValues(args []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, arg := range args {
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("snippet value %q must be of the form key=value", arg)
+		}
+		values[k] = v
+	}
+	return values, nil
+}
+
+// renderSnippet expands the snippet into its final text. The values map is
+// keyed by tabstop number (e.g. "1") or variable name (e.g. "TM_FILENAME").
+// Tabstops without a value use their placeholder default (or the first
+// choice), and variables without a value use their default (or, as VS Code
+// does, the empty string for known variables and the variable name for
+// unknown ones).
+func renderSnippet(s *SnippetDefinition, values map[string]string) (string, error) {
+	elements, err := parseSnippet(s.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse snippet %q: %v", s.Name, err)
+	}
+
+	r := &snippetRenderer{
+		values:    values,
+		defaults:  map[int][]SnippetElement{},
+		choices:   map[int]string{},
+		resolving: map[int]bool{},
+	}
+	walkSnippet(elements, func(e SnippetElement) {
+		switch e := e.(type) {
+		case *SnippetPlaceholder:
+			if _, ok := r.defaults[e.Index]; !ok {
+				r.defaults[e.Index] = e.Default
+			}
+		case *SnippetChoice:
+			if _, ok := r.choices[e.Index]; !ok && len(e.Options) > 0 {
+				r.choices[e.Index] = e.Options[0]
+			}
+		}
+	})
+	return r.render(elements)
+}
+
+type snippetRenderer struct {
+	values    map[string]string
+	defaults  map[int][]SnippetElement
+	choices   map[int]string
+	resolving map[int]bool
+}
+
+func (r *snippetRenderer) render(elements []SnippetElement) (string, error) {
+	var sb strings.Builder
+	for _, e := range elements {
+		var s string
+		var err error
+		switch e := e.(type) {
+		case *SnippetText:
+			s = e.Value
+		case *SnippetTabstop:
+			if s, err = r.tabstop(e.Index); err == nil {
+				s, err = e.Transform.apply(s)
+			}
+		case *SnippetPlaceholder:
+			s, err = r.tabstop(e.Index)
+		case *SnippetChoice:
+			s, err = r.tabstop(e.Index)
+		case *SnippetVariable:
+			if s, err = r.variable(e); err == nil {
+				s, err = e.Transform.apply(s)
+			}
+		default:
+			err = fmt.Errorf("unsupported snippet element %T", e)
+		}
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(s)
+	}
+	return sb.String(), nil
+}
+
+// tabstop returns the value that is inserted for every occurrence of the tabstop.
+func (r *snippetRenderer) tabstop(n int) (string, error) {
+	if v, ok := r.values[strconv.Itoa(n)]; ok {
+		return v, nil
+	}
+	if d, ok := r.defaults[n]; ok {
+		if r.resolving[n] {
+			return "", fmt.Errorf("tabstop %d has a recursive default value", n)
+		}
+		r.resolving[n] = true
+		defer func() { r.resolving[n] = false }()
+		return r.render(d)
+	}
+	return r.choices[n], nil
+}
+
+func (r *snippetRenderer) variable(v *SnippetVariable) (string, error) {
+	if s, ok := r.values[v.Name]; ok {
+		return s, nil
+	}
+	if v.HasDefault {
+		return r.render(v.Default)
+	}
+	if slices.Contains(knownSnippetVariables, v.Name) {
+		return "", nil
+	}
+	return v.Name, nil
+}
+
+// apply runs the transform on the provided value. A nil transform returns
+// the value unchanged.
+func (t *SnippetTransform) apply(value string) (string, error) {
+	if t == nil {
+		return value, nil
+	}
+
+	var flags string
+	for _, o := range t.Options {
+		if strings.ContainsRune("ims", o) {
+			flags += string(o)
+		}
+	}
+	regex := t.Regex
+	if flags != "" {
+		regex = fmt.Sprintf("(?%s)%s", flags, regex)
+	}
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return "", fmt.Errorf("failed to compile transform regex %q: %v", t.Regex, err)
+	}
+
+	matches := re.FindAllStringSubmatchIndex(value, -1)
+	if !strings.ContainsRune(t.Options, 'g') && len(matches) > 1 {
+		matches = matches[:1]
+	}
+
+	var sb strings.Builder
+	var prev int
+	for _, m := range matches {
+		sb.WriteString(value[prev:m[0]])
+		for _, f := range t.Format {
+			sb.WriteString(f.resolve(value, m))
+		}
+		prev = m[1]
+	}
+	sb.WriteString(value[prev:])
+	return sb.String(), nil
+}
+
+// resolve returns the formatted text for the provided regex match (as
+// returned by regexp.FindStringSubmatchIndex).
+func (f *SnippetFormat) resolve(value string, match []int) string {
+	if f.Index == nil {
+		return f.Text
+	}
+
+	var group string
+	if i := *f.Index; 2*i+1 < len(match) && match[2*i] >= 0 {
+		group = value[match[2*i]:match[2*i+1]]
+	}
+
+	switch {
+	case f.Modifier != "":
+		return applySnippetModifier(f.Modifier, group)
+	case f.HasIf && group != "":
+		return f.If
+	case f.HasElse && group == "":
+		return f.Else
+	case f.HasIf:
+		return ""
+	}
+	return group
+}
+
+var (
+	snippetWordRegex = regexp.MustCompile("(?i)[a-z0-9]+")
+)
+
+func applySnippetModifier(modifier, s string) string {
+	switch modifier {
+	case "upcase":
+		return strings.ToUpper(s)
+	case "downcase":
+		return strings.ToLower(s)
+	case "capitalize":
+		return upperFirst(s)
+	case "camelcase", "pascalcase":
+		words := snippetWordRegex.FindAllString(s, -1)
+		for i, w := range words {
+			if i == 0 && modifier == "camelcase" {
+				words[i] = lowerFirst(w)
+			} else {
+				words[i] = upperFirst(w)
+			}
+		}
+		return strings.Join(words, "")
+	}
+	return s
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
@@ -1,19 +1,177 @@
 package main
 
+import (
+	"fmt"
+	"strings"
+)
+
+// See the following link for snippet syntax details:
+// https://code.visualstudio.com/docs/editor/userdefinedsnippets
+
 type Snippet struct {
 	Path     string `json:"path"`
-	Language string `json:"language"`
+	Language string `json:"language,omitempty"`
+}
+
+// SnippetFile is a set of snippets that are written to a single json file
+// (relative to the groog root directory) and contributed for the language.
+type SnippetFile struct {
+	Path     string
+	Language string
+	Snippets []*SnippetDefinition
+}
+
+type SnippetDefinition struct {
+	Name        string
+	Prefixes    []string
+	Body        []string
+	Description string
+	// Scope is a comma-separated list of language identifiers. This is only
+	// relevant for global snippet files, since snippet files contributed for a
+	// language are only applied to that language.
+	Scope string
+}
+
+// snippetJSON is the json representation of a single snippet.
+type snippetJSON struct {
+	Prefix      []string `json:"prefix"`
+	Body        []string `json:"body"`
+	Description string   `json:"description,omitempty"`
+	Scope       string   `json:"scope,omitempty"`
 }
 
 var (
-	Snippets = []*Snippet{
+	SnippetFiles = []*SnippetFile{
 		{
-			"snippets/go-test.json",
-			"go",
+			Path:     "snippets/go-test.json",
+			Language: "go",
+			Snippets: []*SnippetDefinition{
+				{
+					Name:     "Go Test",
+					Prefixes: []string{"gt", "goTest"},
+					Body: []string{
+						fmt.Sprintf("func Test%s(t *testing.T) {", snipPlaceholder(1, "Name")),
+						"  for _, test := range []struct {",
+						"    name string",
+						"  }{",
+						"    {",
+						`      name: "someTest",`,
+						"    },",
+						"  } {",
+						"    t.Run(test.name, func(t *testing.T) {",
+						"    })",
+						"  }",
+						"}",
+					},
+					Description: "Go test function",
+				},
+			},
 		},
 		{
-			"snippets/java-parameterized-test.json",
-			"java",
+			Path:     "snippets/java-parameterized-test.json",
+			Language: "java",
+			Snippets: []*SnippetDefinition{
+				{
+					Name:     "Java Parameterized Test",
+					Prefixes: []string{"jt"},
+					Body: []string{
+						fmt.Sprintf("public static Stream<Arguments> %sTestCases() {", snipPlaceholder(1, "Name")),
+						"    return Stream.of(",
+						"        Arguments.of(",
+						`            "testName"`,
+						"        )",
+						"    );",
+						"}",
+						"",
+						`@ParameterizedTest(name = "{0}")`,
+						fmt.Sprintf(`@MethodSource("%sTestCases")`, snipPlaceholder(1, "Name")),
+						fmt.Sprintf("public void test%s(", snipTransform(1, "(.*)", "${1:/capitalize}", "")),
+						"    final String testName",
+						") {",
+						"",
+						"}",
+					},
+					Description: "Java parameterized test setup",
+				},
+			},
 		},
 	}
 )
+
+// snippetContributions returns the snippet contribution for each snippet file.
+func snippetContributions() []*Snippet {
+	var r []*Snippet
+	for _, sf := range SnippetFiles {
+		r = append(r, &Snippet{sf.Path, sf.Language})
+	}
+	return r
+}
+
+/********************
+ * Snippet builders *
+ ********************/
+
+func snipTabstop(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func snipPlaceholder(n int, defaultValue string) string {
+	return fmt.Sprintf("${%d:%s}", n, defaultValue)
+}
+
+func snipChoice(n int, choices ...string) string {
+	var escaped []string
+	for _, c := range choices {
+		escaped = append(escaped, snipChoiceEscaper.Replace(c))
+	}
+	return fmt.Sprintf("${%d|%s|}", n, strings.Join(escaped, ","))
+}
+
+func snipVariable(name string) string {
+	return fmt.Sprintf("${%s}", name)
+}
+
+func snipVariableDefault(name, defaultValue string) string {
+	return fmt.Sprintf("${%s:%s}", name, defaultValue)
+}
+
+// snipTransform applies a regex transformation to the provided tabstop.
+func snipTransform(n int, regex, format, options string) string {
+	return fmt.Sprintf("${%d/%s/%s/%s}", n, regex, format, options)
+}
+
+// snipVariableTransform applies a regex transformation to the provided variable.
+func snipVariableTransform(name, regex, format, options string) string {
+	return fmt.Sprintf("${%s/%s/%s/%s}", name, regex, format, options)
+}
+
+var (
+	snipChoiceEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `|`, `\|`)
+)
+
+/**************
+ * Generation *
+ **************/
+
+// json returns the json representation of the snippet file.
+func (sf *SnippetFile) json() (map[string]*snippetJSON, error) {
+	m := map[string]*snippetJSON{}
+	for _, s := range sf.Snippets {
+		if _, ok := m[s.Name]; ok {
+			return nil, fmt.Errorf("%s: duplicate snippet name %q", sf.Path, s.Name)
+		}
+		if len(s.Prefixes) == 0 {
+			return nil, fmt.Errorf("%s: snippet %q has no prefixes", sf.Path, s.Name)
+		}
+		if issues := lintSnippet(s); len(issues) > 0 {
+			return nil, fmt.Errorf("%s: snippet %q is invalid:\n%s", sf.Path, s.Name, strings.Join(issues, "\n"))
+		}
+		m[s.Name] = &snippetJSON{
+			Prefix:      s.Prefixes,
+			Body:        s.Body,
+			Description: s.Description,
+			Scope:       s.Scope,
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	updateGolden = flag.Bool("update", false, "Whether or not to update the golden files")
+)
+
+// checkGolden compares got against the contents of the provided golden file
+// (relative to the testdata directory). If the -update flag is set, then the
+// golden file is overwritten instead.
+func checkGolden(t *testing.T, goldenFile, got string) {
+	t.Helper()
+
+	filename := filepath.Join("testdata", filepath.FromSlash(goldenFile))
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(filename, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match golden file %s (run with -update to update it):\n--- want ---\n%s\n--- got ---\n%s", filename, want, got)
+	}
+}
+
+// checkSnippetGolden renders the named snippet from SnippetFiles with the
+// provided values and compares the result against a golden file.
+func checkSnippetGolden(t *testing.T, name string, values map[string]string, goldenFile string) {
+	t.Helper()
+
+	s, err := findSnippet(name)
+	if err != nil {
+		t.Fatalf("findSnippet(%q) returned error: %v", name, err)
+	}
+	got, err := renderSnippet(s, values)
+	if err != nil {
+		t.Fatalf("renderSnippet(%q) returned error: %v", name, err)
+	}
+	checkGolden(t, goldenFile, got)
+}
+
+func TestSnippets(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		snippet string
+		values  map[string]string
+		golden  string
+	}{
+		{
+			name:    "Go Test with defaults",
+			snippet: "Go Test",
+			golden:  "snippets/go-test-defaults.golden",
+		},
+		{
+			name:    "Go Test with name",
+			snippet: "Go Test",
+			values:  map[string]string{"1": "Parse"},
+			golden:  "snippets/go-test-name.golden",
+		},
+		{
+			name:    "Java Parameterized Test capitalizes test method",
+			snippet: "Java Parameterized Test",
+			values:  map[string]string{"1": "parseInput"},
+			golden:  "snippets/java-parameterized-test.golden",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			checkSnippetGolden(t, test.snippet, test.values, test.golden)
+		})
+	}
+}
+
+func TestRenderSnippet(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		body    []string
+		values  map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "renders text",
+			body: []string{"abc", "def"},
+			want: "abc\ndef",
+		},
+		{
+			name: "uses placeholder default for every tabstop occurrence",
+			body: []string{"${1:one} $1 ${1/(.*)/${1:/upcase}/}"},
+			want: "one one ONE",
+		},
+		{
+			name:   "uses provided tabstop values",
+			body:   []string{"${1:one} ${2|a,b|} $2"},
+			values: map[string]string{"1": "uno", "2": "b"},
+			want:   "uno b b",
+		},
+		{
+			name: "uses first choice",
+			body: []string{"${1|a,b|}"},
+			want: "a",
+		},
+		{
+			name: "uses nested placeholder defaults",
+			body: []string{"${1:a ${2:b}} $2"},
+			want: "a b b",
+		},
+		{
+			name:   "renders variables",
+			body:   []string{"$TM_FILENAME ${TM_SELECTED_TEXT} ${UNKNOWN_VAR} ${CLIPBOARD:clip}"},
+			values: map[string]string{"TM_FILENAME": "file.go"},
+			want:   "file.go  UNKNOWN_VAR clip",
+		},
+		{
+			name: "applies case modifiers",
+			body: []string{
+				"${1:hello_big-world}",
+				"${1/(.*)/${1:/capitalize}/}",
+				"${1/(.*)/${1:/camelcase}/}",
+				"${1/(.*)/${1:/pascalcase}/}",
+				"${1/(.*)/${1:/upcase}/}",
+			},
+			want: strings.Join([]string{
+				"hello_big-world",
+				"Hello_big-world",
+				"helloBigWorld",
+				"HelloBigWorld",
+				"HELLO_BIG-WORLD",
+			}, "\n"),
+		},
+		{
+			name:   "applies transform to variable",
+			body:   []string{"${TM_FILENAME/(.*)\\.go$/${1}_test.go/}"},
+			values: map[string]string{"TM_FILENAME": "main.go"},
+			want:   "main_test.go",
+		},
+		{
+			name: "only replaces first match without global option",
+			body: []string{"${1:a-b-c} ${1/-/_/} ${1/-/_/g}"},
+			want: "a-b-c a_b-c a_b_c",
+		},
+		{
+			name: "applies conditional formats",
+			body: []string{"${1:x} ${1/(y)?.*/${1:?yes:no}/} ${1/(x)?.*/${1:+yes}/} ${1/(y)?.*/${1:-none}/}"},
+			want: "x no yes none",
+		},
+		{
+			name: "applies case-insensitive option",
+			body: []string{"${1:ABC} ${1/b/-/i}"},
+			want: "ABC A-C",
+		},
+		{
+			name:    "fails for invalid snippet",
+			body:    []string{"${1:abc"},
+			wantErr: "failed to parse snippet",
+		},
+		{
+			name:    "fails for recursive default",
+			body:    []string{"${1:${1:a}}"},
+			wantErr: "tabstop 1 has a recursive default value",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := renderSnippet(&SnippetDefinition{Name: "test", Body: test.body}, test.values)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("renderSnippet() returned error %v; want error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderSnippet() returned error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("renderSnippet() returned\n%q\nwant\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSnippetElementsString(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "tabstops",
+			body: "$1 ${2} ${1}2",
+			want: snipTabstop(1) + " " + snipTabstop(2) + " ${1}2",
+		},
+		{
+			name: "placeholders and choices",
+			body: "${1:a $2} ${3|x,y\\,z|}",
+			want: snipPlaceholder(1, "a "+snipTabstop(2)) + " " + snipChoice(3, "x", "y,z"),
+		},
+		{
+			name: "variables",
+			body: "$TM_FILENAME ${CLIPBOARD:clip}",
+			want: snipVariable("TM_FILENAME") + " " + snipVariableDefault("CLIPBOARD", "clip"),
+		},
+		{
+			name: "tabstop transform",
+			body: "${1/(.*)/${1:/capitalize}/}",
+			want: snipTransform(1, "(.*)", "${1:/capitalize}", ""),
+		},
+		{
+			name: "variable transform",
+			body: "${TM_FILENAME/(.*)\\/(.*)/${2:+found}\\//g}",
+			want: snipVariableTransform("TM_FILENAME", "(.*)\\/(.*)", "${2:+found}\\/", "g"),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			elements, err := parseSnippet([]string{test.body})
+			if err != nil {
+				t.Fatalf("parseSnippet() returned error: %v", err)
+			}
+			if got := snippetElementsString(elements, false); got != test.want {
+				t.Errorf("snippetElementsString() returned %q; want %q", got, test.want)
+			}
+		})
+	}
+}
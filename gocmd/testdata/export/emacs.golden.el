@@ -0,0 +1,92 @@
+;;; groog.el --- Generated by `vs-package export emacs`. Do not edit.  -*- lexical-binding: t -*-
+
+(define-key global-map (kbd "M-b") #'backward-word) ; groog.cursorWordLeft
+(define-key global-map (kbd "M-DEL") #'backward-kill-word) ; groog.deleteWordLeft
+(define-key global-map (kbd "M-d") #'kill-word) ; groog.deleteWordRight
+(define-key global-map (kbd "M-<delete>") #'kill-word) ; groog.deleteWordRight
+(define-key global-map (kbd "M-e") #'kmacro-end-and-call-macro) ; groog.record.playRecording
+(define-key global-map (kbd "M-f") #'forward-word) ; groog.cursorWordRight
+(define-key global-map (kbd "M-h") #'backward-kill-word) ; groog.deleteWordLeft
+(define-key global-map (kbd "M-S-<f4>") #'save-buffers-kill-terminal) ; workbench.action.closeWindow
+(define-key global-map (kbd "M-x") #'execute-extended-command) ; workbench.action.showCommands
+(define-key global-map (kbd "M-y") #'yank) ; editor.action.clipboardPasteAction
+(define-key global-map (kbd "DEL") #'delete-backward-char) ; groog.deleteLeft
+(define-key global-map (kbd "C-.") #'customize) ; workbench.action.openSettings
+(define-key global-map (kbd "C-/") #'undo) ; groog.undo
+(define-key global-map (kbd "C-;") #'comment-line) ; editor.action.commentLine
+(define-key global-map (kbd "C-a") #'move-beginning-of-line) ; groog.cursorHome
+(define-key global-map (kbd "C-b") #'backward-char) ; groog.cursorLeft
+(define-key global-map (kbd "C-DEL") #'backward-kill-word) ; groog.deleteWordLeft
+(define-key global-map (kbd "C-d") #'delete-char) ; groog.deleteRight
+(define-key global-map (kbd "C-<delete>") #'kill-word) ; groog.deleteWordRight
+(define-key global-map (kbd "C-e") #'move-end-of-line) ; groog.cursorEnd
+(define-key global-map (kbd "C-f") #'forward-char) ; groog.cursorRight
+(define-key global-map (kbd "C-h") #'delete-backward-char) ; groog.deleteLeft
+(define-key global-map (kbd "C-i") #'indent-rigidly-right-to-tab-stop) ; editor.action.indentLines
+(define-key global-map (kbd "C-j") #'set-mark-command) ; groog.toggleMarkMode
+(define-key global-map (kbd "C-k") #'kill-line) ; groog.kill
+(define-key global-map (kbd "C-l") #'scroll-down-command) ; groog.jump
+(define-key global-map (kbd "C-<left>") #'backward-word) ; groog.cursorWordLeft
+(define-key global-map (kbd "C-n") #'next-line) ; groog.cursorDown
+(define-key global-map (kbd "C-o") #'next-buffer) ; groog.focusNextEditor
+(define-key global-map (kbd "C-p") #'previous-line) ; groog.cursorUp
+(define-key global-map (kbd "C-<next>") #'next-buffer) ; groog.focusNextEditor
+(define-key global-map (kbd "C-<prior>") #'previous-buffer) ; groog.focusPreviousEditor
+(define-key global-map (kbd "C-q") #'delete-window) ; workbench.action.closeEditorsAndGroup
+(define-key global-map (kbd "C-r") #'isearch-backward) ; groog.reverseFind
+(define-key global-map (kbd "C-<right>") #'forward-word) ; groog.cursorWordRight
+(define-key global-map (kbd "C-s") #'isearch-forward) ; groog.find
+(define-key global-map (kbd "C-S-/") #'undo-redo) ; groog.redo
+(define-key global-map (kbd "C-S-a") #'mark-whole-buffer) ; editor.action.selectAll
+(define-key global-map (kbd "C-S-f") #'rgrep) ; workbench.action.findInFiles
+(define-key global-map (kbd "C-S-<home>") #'mark-whole-buffer) ; editor.action.selectAll
+(define-key global-map (kbd "C-S-i") #'indent-rigidly-left-to-tab-stop) ; editor.action.outdentLines
+(define-key global-map (kbd "C-S-l") #'scroll-down-command) ; groog.jump
+(define-key global-map (kbd "C-S-s") #'rgrep) ; workbench.action.findInFiles
+(define-key global-map (kbd "C-S-v") #'scroll-up-command) ; groog.fall
+(define-key global-map (kbd "C-u") #'previous-buffer) ; groog.focusPreviousEditor
+(define-key global-map (kbd "C-v") #'scroll-up-command) ; groog.fall
+(define-key global-map (kbd "C-w") #'yank) ; groog.yank
+(define-key global-map (kbd "C-x d") #'xref-find-definitions) ; editor.action.revealDefinition
+(define-key global-map (kbd "C-x C-d") #'xref-find-definitions) ; editor.action.revealDefinition
+(define-key global-map (kbd "C-x f") #'find-file) ; workbench.action.quickOpen
+(define-key global-map (kbd "C-x C-f") #'find-file) ; workbench.action.quickOpen
+(define-key global-map (kbd "C-x l") #'goto-line) ; workbench.action.gotoLine
+(define-key global-map (kbd "C-x C-l") #'goto-line) ; workbench.action.gotoLine
+(define-key global-map (kbd "C-x n") #'end-of-buffer) ; groog.cursorBottom
+(define-key global-map (kbd "C-x C-n") #'end-of-buffer) ; groog.cursorBottom
+(define-key global-map (kbd "C-x o") #'recentf-open-files) ; workbench.action.openRecent
+(define-key global-map (kbd "C-x C-o") #'recentf-open-files) ; workbench.action.openRecent
+(define-key global-map (kbd "C-x p") #'beginning-of-buffer) ; groog.cursorTop
+(define-key global-map (kbd "C-x C-p") #'beginning-of-buffer) ; groog.cursorTop
+(define-key global-map (kbd "C-x s") #'save-buffer) ; workbench.action.files.save
+(define-key global-map (kbd "C-x C-s") #'save-buffer) ; workbench.action.files.save
+(define-key global-map (kbd "C-x S-<insert>") #'yank) ; editor.action.clipboardPasteAction
+(define-key global-map (kbd "C-x C-S-<insert>") #'yank) ; editor.action.clipboardPasteAction
+(define-key global-map (kbd "C-x x") #'kmacro-start-macro) ; groog.record.startRecording
+(define-key global-map (kbd "C-x C-x") #'kmacro-start-macro) ; groog.record.startRecording
+(define-key global-map (kbd "C-x y") #'yank) ; editor.action.clipboardPasteAction
+(define-key global-map (kbd "C-x C-y") #'yank) ; editor.action.clipboardPasteAction
+(define-key global-map (kbd "C-z b") #'vc-annotate) ; gitlens.toggleLineBlame
+(define-key global-map (kbd "C-z C-b") #'vc-annotate) ; gitlens.toggleLineBlame
+(define-key global-map (kbd "C-z f") #'save-buffer) ; workbench.action.files.saveWithoutFormatting
+(define-key global-map (kbd "C-z C-f") #'save-buffer) ; workbench.action.files.saveWithoutFormatting
+(define-key global-map (kbd "C-z <left>") #'vc-annotate) ; gitlens.toggleLineBlame
+(define-key global-map (kbd "C-z C-<left>") #'vc-annotate) ; gitlens.toggleLineBlame
+(define-key global-map (kbd "C-z s") #'save-buffer) ; workbench.action.files.saveWithoutFormatting
+(define-key global-map (kbd "C-z C-s") #'save-buffer) ; workbench.action.files.saveWithoutFormatting
+(define-key global-map (kbd "C-z x") #'execute-extended-command) ; workbench.action.showCommands
+(define-key global-map (kbd "C-z C-x") #'execute-extended-command) ; workbench.action.showCommands
+(define-key global-map (kbd "<delete>") #'delete-char) ; groog.deleteRight
+(define-key global-map (kbd "<down>") #'next-line) ; groog.cursorDown
+(define-key global-map (kbd "<end>") #'move-end-of-line) ; groog.cursorEnd
+(define-key global-map (kbd "<home>") #'move-beginning-of-line) ; groog.cursorHome
+(define-key global-map (kbd "<left>") #'backward-char) ; groog.cursorLeft
+(define-key global-map (kbd "<next>") #'scroll-up-command) ; groog.fall
+(define-key global-map (kbd "<prior>") #'scroll-down-command) ; groog.jump
+(define-key global-map (kbd "<right>") #'forward-char) ; groog.cursorRight
+(define-key global-map (kbd "S-DEL") #'project-query-replace-regexp) ; workbench.action.replaceInFiles
+(define-key global-map (kbd "S-<home>") #'mark-whole-buffer) ; editor.action.selectAll
+(define-key global-map (kbd "S-<next>") #'scroll-up-command) ; groog.fall
+(define-key global-map (kbd "S-<prior>") #'scroll-down-command) ; groog.jump
+(define-key global-map (kbd "<up>") #'previous-line) ; groog.cursorUp
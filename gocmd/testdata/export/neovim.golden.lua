@@ -0,0 +1,81 @@
+-- Generated by `vs-package export neovim`. Do not edit.
+local map = vim.keymap.set
+
+map("n", "<M-b>", "b", { desc = "groog.cursorWordLeft" })
+map("n", "<M-BS>", "db", { desc = "groog.deleteWordLeft" })
+map("n", "<M-d>", "dw", { desc = "groog.deleteWordRight" })
+map("n", "<M-Del>", "dw", { desc = "groog.deleteWordRight" })
+map("n", "<M-e>", "@q", { desc = "groog.record.playRecording" })
+map("n", "<M-f>", "w", { desc = "groog.cursorWordRight" })
+map("n", "<M-h>", "db", { desc = "groog.deleteWordLeft" })
+map("n", "<M-S-F4>", "<cmd>qall<CR>", { desc = "workbench.action.closeWindow" })
+map("n", "<M-y>", "p", { desc = "editor.action.clipboardPasteAction" })
+map("n", "<BS>", "X", { desc = "groog.deleteLeft" })
+map("n", "<C-/>", "u", { desc = "groog.undo" })
+map("n", "<C-a>", "0", { desc = "groog.cursorHome" })
+map("n", "<C-b>", "h", { desc = "groog.cursorLeft" })
+map("n", "<C-BS>", "db", { desc = "groog.deleteWordLeft" })
+map("n", "<C-d>", "x", { desc = "groog.deleteRight" })
+map("n", "<C-Del>", "dw", { desc = "groog.deleteWordRight" })
+map("n", "<C-e>", "$", { desc = "groog.cursorEnd" })
+map("n", "<C-f>", "l", { desc = "groog.cursorRight" })
+map("n", "<C-h>", "X", { desc = "groog.deleteLeft" })
+map("n", "<C-i>", ">>", { desc = "editor.action.indentLines" })
+map("n", "<C-j>", "v", { desc = "groog.toggleMarkMode" })
+map("n", "<C-k>", "D", { desc = "groog.kill" })
+map("n", "<C-l>", "<C-u>", { desc = "groog.jump" })
+map("n", "<C-Left>", "b", { desc = "groog.cursorWordLeft" })
+map("n", "<C-n>", "j", { desc = "groog.cursorDown" })
+map("n", "<C-o>", "<cmd>bnext<CR>", { desc = "groog.focusNextEditor" })
+map("n", "<C-p>", "k", { desc = "groog.cursorUp" })
+map("n", "<C-PageDown>", "<cmd>bnext<CR>", { desc = "groog.focusNextEditor" })
+map("n", "<C-PageUp>", "<cmd>bprevious<CR>", { desc = "groog.focusPreviousEditor" })
+map("n", "<C-q>", "<cmd>close<CR>", { desc = "workbench.action.closeEditorsAndGroup" })
+map("n", "<C-r>", "?", { desc = "groog.reverseFind" })
+map("n", "<C-Right>", "w", { desc = "groog.cursorWordRight" })
+map("n", "<C-s>", "/", { desc = "groog.find" })
+map("n", "<C-S-/>", "<C-r>", { desc = "groog.redo" })
+map("n", "<C-S-a>", "ggVG", { desc = "editor.action.selectAll" })
+map("n", "<C-S-Home>", "ggVG", { desc = "editor.action.selectAll" })
+map("n", "<C-S-i>", "<<", { desc = "editor.action.outdentLines" })
+map("n", "<C-S-l>", "<C-u>", { desc = "groog.jump" })
+map("n", "<C-S-n>", "n", { desc = "groog.find.next" })
+map("n", "<C-S-p>", "N", { desc = "groog.find.previous" })
+map("n", "<C-S-v>", "<C-d>", { desc = "groog.fall" })
+map("n", "<C-u>", "<cmd>bprevious<CR>", { desc = "groog.focusPreviousEditor" })
+map("n", "<C-v>", "<C-d>", { desc = "groog.fall" })
+map("n", "<C-w>", "p", { desc = "groog.yank" })
+map("n", "<C-x>d", "<cmd>lua vim.lsp.buf.definition()<CR>", { desc = "editor.action.revealDefinition" })
+map("n", "<C-x><C-d>", "<cmd>lua vim.lsp.buf.definition()<CR>", { desc = "editor.action.revealDefinition" })
+map("n", "<C-x>n", "G", { desc = "groog.cursorBottom" })
+map("n", "<C-x><C-n>", "G", { desc = "groog.cursorBottom" })
+map("n", "<C-x>p", "gg", { desc = "groog.cursorTop" })
+map("n", "<C-x><C-p>", "gg", { desc = "groog.cursorTop" })
+map("n", "<C-x>s", "<cmd>write<CR>", { desc = "workbench.action.files.save" })
+map("n", "<C-x><C-s>", "<cmd>write<CR>", { desc = "workbench.action.files.save" })
+map("n", "<C-x><S-Insert>", "p", { desc = "editor.action.clipboardPasteAction" })
+map("n", "<C-x><C-S-Insert>", "p", { desc = "editor.action.clipboardPasteAction" })
+map("n", "<C-x><Tab>", "<cmd>lua vim.lsp.buf.format()<CR>", { desc = "groog.format" })
+map("n", "<C-x><C-Tab>", "<cmd>lua vim.lsp.buf.format()<CR>", { desc = "groog.format" })
+map("n", "<C-x>x", "qq", { desc = "groog.record.startRecording" })
+map("n", "<C-x><C-x>", "qq", { desc = "groog.record.startRecording" })
+map("n", "<C-x>y", "p", { desc = "editor.action.clipboardPasteAction" })
+map("n", "<C-x><C-y>", "p", { desc = "editor.action.clipboardPasteAction" })
+map("n", "<C-z>f", "<cmd>noautocmd write<CR>", { desc = "workbench.action.files.saveWithoutFormatting" })
+map("n", "<C-z><C-f>", "<cmd>noautocmd write<CR>", { desc = "workbench.action.files.saveWithoutFormatting" })
+map("n", "<C-z>s", "<cmd>noautocmd write<CR>", { desc = "workbench.action.files.saveWithoutFormatting" })
+map("n", "<C-z><C-s>", "<cmd>noautocmd write<CR>", { desc = "workbench.action.files.saveWithoutFormatting" })
+map("n", "<Del>", "x", { desc = "groog.deleteRight" })
+map("n", "<Down>", "j", { desc = "groog.cursorDown" })
+map("n", "<End>", "$", { desc = "groog.cursorEnd" })
+map("n", "<Home>", "0", { desc = "groog.cursorHome" })
+map("n", "<Left>", "h", { desc = "groog.cursorLeft" })
+map("n", "<PageDown>", "<C-d>", { desc = "groog.fall" })
+map("n", "<PageUp>", "<C-u>", { desc = "groog.jump" })
+map("n", "<Right>", "l", { desc = "groog.cursorRight" })
+map("n", "<S-Down>", "n", { desc = "groog.find.next" })
+map("n", "<S-Home>", "ggVG", { desc = "editor.action.selectAll" })
+map("n", "<S-PageDown>", "<C-d>", { desc = "groog.fall" })
+map("n", "<S-PageUp>", "<C-u>", { desc = "groog.jump" })
+map("n", "<S-Up>", "N", { desc = "groog.find.previous" })
+map("n", "<Up>", "k", { desc = "groog.cursorUp" })
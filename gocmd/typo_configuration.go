@@ -1,5 +1,188 @@
 package main
 
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Correction is the go representation of the Correction interface in typos.ts.
+type Correction struct {
+	Languages                    []string          `json:"languages,omitempty"`
+	Words                        map[string]string `json:"words"`
+	BreakCharacters              string            `json:"breakCharacters,omitempty"`
+	ReplacementSuffix            string            `json:"replacementSuffix,omitempty"`
+	ReplacementSuffixAfterCursor string            `json:"replacementSuffixAfterCursor,omitempty"`
+	ExcludeBreakCharacter        bool              `json:"excludeBreakCharacter,omitempty"`
+}
+
+// The language key used for corrections that apply to all languages.
+const globalLanguageKey = "*"
+
+// The built-in corrections. Corrections without any languages are contributed
+// as the default `groog.typos` value, and the rest are contributed as
+// language-scoped defaults (see typosConfigurationDefaults).
+//
+//go:embed default_typos.json
+var defaultTyposJSON []byte
+
+// defaultCorrections parses, validates, and returns the built-in corrections.
+func defaultCorrections() ([]*Correction, error) {
+	return parseCorrections("default_typos.json", defaultTyposJSON)
+}
+
+// splitDefaultCorrections returns the built-in corrections that apply to all
+// languages and those that are language-specific (respectively).
+func splitDefaultCorrections() ([]*Correction, []*Correction, error) {
+	corrections, err := defaultCorrections()
+	if err != nil {
+		return nil, nil, err
+	}
+	global, languageSpecific := splitCorrections(corrections)
+	return global, languageSpecific, nil
+}
+
+// splitCorrections returns the corrections that apply to all languages and
+// those that are language-specific (respectively).
+func splitCorrections(corrections []*Correction) ([]*Correction, []*Correction) {
+	var global, languageSpecific []*Correction
+	for _, c := range corrections {
+		if len(c.Languages) == 0 || slices.Contains(c.Languages, globalLanguageKey) {
+			global = append(global, c)
+		} else {
+			languageSpecific = append(languageSpecific, c)
+		}
+	}
+	return global, languageSpecific
+}
+
+func parseCorrections(name string, b []byte) ([]*Correction, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %v", name, err)
+	}
+	if err := validationError(name, typosSchema().validate(name, v)); err != nil {
+		return nil, err
+	}
+
+	dups, err := duplicateJSONKeys(name, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %v", name, err)
+	}
+	if len(dups) > 0 {
+		return nil, fmt.Errorf("%s contains duplicate keys: %v", name, dups)
+	}
+
+	var corrections []*Correction
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&corrections); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s into corrections: %v", name, err)
+	}
+
+	var invalid []string
+	for i, c := range corrections {
+		if len(c.Words) == 0 {
+			invalid = append(invalid, fmt.Sprintf("correction %d has no words", i))
+		}
+		for _, typo := range sortedKeys(c.Words) {
+			if typo == "" {
+				invalid = append(invalid, fmt.Sprintf("correction %d has an empty typo", i))
+			} else if c.Words[typo] == "" {
+				invalid = append(invalid, fmt.Sprintf("typo %q in correction %d has an empty correction", typo, i))
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("%s contains invalid corrections:\n%s", name, strings.Join(invalid, "\n"))
+	}
+
+	if err := checkCorrectionConflicts(corrections); err != nil {
+		return nil, fmt.Errorf("%s contains conflicting corrections:\n%v", name, err)
+	}
+	return corrections, nil
+}
+
+// checkCorrectionConflicts returns an error if the same typo is corrected in
+// different ways for the same language (and an overlapping set of break characters).
+func checkCorrectionConflicts(corrections []*Correction) error {
+	type entry struct {
+		index      int
+		correction *Correction
+	}
+	// Map from language to typo to corrections for that typo
+	byLanguage := map[string]map[string][]*entry{}
+
+	var conflicts []string
+	for i, c := range corrections {
+		languages := c.Languages
+		if len(languages) == 0 {
+			languages = []string{globalLanguageKey}
+		}
+		for _, lang := range languages {
+			if byLanguage[lang] == nil {
+				byLanguage[lang] = map[string][]*entry{}
+			}
+			for typo := range c.Words {
+				for _, prev := range byLanguage[lang][typo] {
+					if c.conflicts(prev.correction, typo) {
+						conflicts = append(conflicts, fmt.Sprintf("typo %q for language %q is defined differently in corrections %d and %d", typo, lang, prev.index, i))
+					}
+				}
+				byLanguage[lang][typo] = append(byLanguage[lang][typo], &entry{i, c})
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("%s", strings.Join(conflicts, "\n"))
+	}
+	return nil
+}
+
+// conflicts returns whether the two corrections would apply different
+// corrections for the provided typo.
+func (c *Correction) conflicts(other *Correction, typo string) bool {
+	// Empty break characters means the default break characters are used.
+	if c.BreakCharacters != "" && other.BreakCharacters != "" && !strings.ContainsAny(c.BreakCharacters, other.BreakCharacters) {
+		return false
+	}
+
+	return c.Words[typo] != other.Words[typo] ||
+		c.ReplacementSuffix != other.ReplacementSuffix ||
+		c.ReplacementSuffixAfterCursor != other.ReplacementSuffixAfterCursor ||
+		c.ExcludeBreakCharacter != other.ExcludeBreakCharacter
+}
+
+// typosConfigurationDefaults groups the provided corrections by language and
+// returns the `configurationDefaults` entries for each language (e.g.
+// `"[go]": { "groog.typos": [...] }`).
+func typosConfigurationDefaults(corrections []*Correction) map[string]map[string]interface{} {
+	byLanguage := map[string][]*Correction{}
+	for _, c := range corrections {
+		for _, lang := range c.Languages {
+			// Languages are implied by the language-scoped setting.
+			lc := *c
+			lc.Languages = nil
+			byLanguage[lang] = append(byLanguage[lang], &lc)
+		}
+	}
+
+	m := map[string]map[string]interface{}{}
+	for lang, cs := range byLanguage {
+		m[fmt.Sprintf("[%s]", lang)] = map[string]interface{}{
+			"groog.typos": cs,
+		}
+	}
+	return m
+}
+
 func goplsSchema() *JSONSchema {
 	// This is not a registered configuration so it can't be updated automatically in settings.ts.
 	// We get around this issue by adding the configuration ourselves :)
@@ -11,8 +194,19 @@ func goplsSchema() *JSONSchema {
 	})
 }
 
-func typosSchema() *JSONSchema {
-	return NewJSONArray(correctionSchema(), JSONDescription("List of corrections to automatically fix."))
+func typosSchema(opts ...JSONSchemaOption) *JSONSchema {
+	opts = append(opts,
+		JSONMarkdownDescription("List of corrections to automatically fix. Language-specific corrections can be configured in language-scoped settings (e.g. `\"[go]\": { \"groog.typos\": [...] }`)."),
+		JSONScope("language-overridable"),
+	)
+	return NewJSONArray(correctionSchema(), opts...)
+}
+
+func includeDefaultTyposSchema() *JSONSchema {
+	return NewJSONBool(
+		JSONMarkdownDescription("Whether the built-in corrections should be applied in addition to the corrections in `#groog.typos#`."),
+		JSONDefault(true),
+	)
 }
 
 func correctionSchema() *JSONSchema {
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTyposConfigurationDefaults(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		corrections []*Correction
+		want        string
+	}{
+		{
+			name: "no corrections",
+			want: `{}`,
+		},
+		{
+			name: "splits corrections by language",
+			corrections: []*Correction{
+				{Languages: []string{"go"}, Words: map[string]string{"fpl": "fmt.Println"}, ReplacementSuffix: "("},
+				{Languages: []string{"typescript", "javascript"}, Words: map[string]string{"cl": "console.log"}},
+				{Languages: []string{"typescript"}, Words: map[string]string{"rso": "runSolo: true,"}, ExcludeBreakCharacter: true},
+			},
+			want: strings.Join([]string{
+				`{"[go]":{"groog.typos":[{"words":{"fpl":"fmt.Println"},"replacementSuffix":"("}]},`,
+				`"[javascript]":{"groog.typos":[{"words":{"cl":"console.log"}}]},`,
+				`"[typescript]":{"groog.typos":[{"words":{"cl":"console.log"}},{"words":{"rso":"runSolo: true,"},"excludeBreakCharacter":true}]}}`,
+			}, ""),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := compactJson(typosConfigurationDefaults(test.corrections)); got != test.want {
+				t.Errorf("typosConfigurationDefaults() returned %s; want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGroogConfigurationDefaults(t *testing.T) {
+	defaults, err := groogConfigurationDefaults()
+	if err != nil {
+		t.Fatalf("groogConfigurationDefaults() returned error: %v", err)
+	}
+
+	if got, want := strings.Join(sortedKeys(defaults), ","), "[go],[java],[javascript],[typescript]"; got != want {
+		t.Errorf("groogConfigurationDefaults() returned languages %s; want %s", got, want)
+	}
+
+	for _, test := range []struct {
+		language    string
+		wantTypos   []string
+		unwantTypos []string
+	}{
+		{"[go]", []string{"fpl", "spf", "sj"}, []string{"buidl", "cl", "jsf"}},
+		{"[java]", []string{"jsf", "jcl"}, []string{"buidl", "fpl", "cl"}},
+		{"[javascript]", []string{"si", "cl"}, []string{"buidl", "rso"}},
+		{"[typescript]", []string{"si", "cl", "rso"}, []string{"buidl", "fpl"}},
+	} {
+		t.Run(test.language, func(t *testing.T) {
+			if got := strings.Join(sortedKeys(defaults[test.language]), ","); got != "groog.typos" {
+				t.Fatalf("groogConfigurationDefaults()[%q] sets %s; want groog.typos", test.language, got)
+			}
+			corrections, ok := defaults[test.language]["groog.typos"].([]*Correction)
+			if !ok {
+				t.Fatalf("groogConfigurationDefaults()[%q][groog.typos] is %T; want []*Correction", test.language, defaults[test.language]["groog.typos"])
+			}
+
+			words := map[string]bool{}
+			for _, c := range corrections {
+				if len(c.Languages) > 0 {
+					t.Errorf("groogConfigurationDefaults()[%q] contains a correction with languages %v", test.language, c.Languages)
+				}
+				for w := range c.Words {
+					words[w] = true
+				}
+			}
+			for _, w := range test.wantTypos {
+				if !words[w] {
+					t.Errorf("groogConfigurationDefaults()[%q] doesn't correct %q", test.language, w)
+				}
+			}
+			for _, w := range test.unwantTypos {
+				if words[w] {
+					t.Errorf("groogConfigurationDefaults()[%q] unexpectedly corrects %q", test.language, w)
+				}
+			}
+		})
+	}
+}
+
+func TestTyposSetting(t *testing.T) {
+	props, err := groogConfigurationProperties()
+	if err != nil {
+		t.Fatalf("groogConfigurationProperties() returned error: %v", err)
+	}
+
+	typos := props["groog.typos"].evaluate()
+	if got := typos["scope"]; got != "language-overridable" {
+		t.Errorf("groog.typos has scope %v; want language-overridable", got)
+	}
+
+	// Only the corrections for all languages are in the default value.
+	corrections, ok := typos["default"].([]*Correction)
+	if !ok {
+		t.Fatalf("groog.typos default is %T; want []*Correction", typos["default"])
+	}
+	for _, c := range corrections {
+		if len(c.Languages) > 0 {
+			t.Errorf("groog.typos default contains a correction with languages %v", c.Languages)
+		}
+	}
+	if len(corrections) == 0 || corrections[0].Words["buidl"] != "build" {
+		t.Errorf("groog.typos default is %s; want the global corrections", compactJson(corrections))
+	}
+}
+
+func TestParseCorrections(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		json    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "empty list",
+			json: `[]`,
+			want: `[]`,
+		},
+		{
+			name: "parses corrections",
+			json: `[{"words": {"teh": "the"}}, {"languages": ["go"], "words": {"fucn": "func"}, "breakCharacters": " "}]`,
+			want: `[{"words":{"teh":"the"}},{"languages":["go"],"words":{"fucn":"func"},"breakCharacters":" "}]`,
+		},
+		{
+			name:    "invalid json",
+			json:    `[{"words": }]`,
+			wantErr: "failed to unmarshal f.json: invalid character '}' looking for beginning of value",
+		},
+		{
+			name:    "not a list",
+			json:    `{}`,
+			wantErr: "f.json failed schema validation:\nf.json: expected array; got object (map[])",
+		},
+		{
+			name:    "non-object entry",
+			json:    `[1]`,
+			wantErr: "f.json failed schema validation:\nf.json[0]: expected object; got number (1)",
+		},
+		{
+			name:    "unknown field",
+			json:    `[{"words": {"teh": "the"}, "foo": "bar"}]`,
+			wantErr: `failed to unmarshal f.json into corrections: json: unknown field "foo"`,
+		},
+		{
+			name:    "empty entry",
+			json:    `[{"words": {"teh": "the"}}, {}]`,
+			wantErr: "f.json contains invalid corrections:\ncorrection 1 has no words",
+		},
+		{
+			name:    "empty words",
+			json:    `[{"words": {}}]`,
+			wantErr: "f.json contains invalid corrections:\ncorrection 0 has no words",
+		},
+		{
+			name:    "empty typo and correction",
+			json:    `[{"words": {"": "the", "teh": ""}}]`,
+			wantErr: "f.json contains invalid corrections:\ncorrection 0 has an empty typo\ntypo \"teh\" in correction 0 has an empty correction",
+		},
+		{
+			name:    "duplicate keys",
+			json:    `[{"words": {"teh": "the", "teh": "tea"}}, {"words": {"a": "b"}, "words": {"c": "d"}}]`,
+			wantErr: "f.json contains duplicate keys: [f.json[0].words.teh f.json[1].words]",
+		},
+		{
+			name:    "conflicting corrections",
+			json:    `[{"words": {"teh": "the"}}, {"words": {"teh": "tea"}}]`,
+			wantErr: "f.json contains conflicting corrections:\ntypo \"teh\" for language \"*\" is defined differently in corrections 0 and 1",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseCorrections("f.json", []byte(test.json))
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("parseCorrections() returned error %v; want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCorrections() returned error: %v", err)
+			}
+			if compactJson(got) != test.want {
+				t.Errorf("parseCorrections() returned %s; want %s", compactJson(got), test.want)
+			}
+		})
+	}
+}
+
+func TestCheckCorrectionConflicts(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		corrections []*Correction
+		want        []string
+	}{
+		{
+			name: "no corrections",
+		},
+		{
+			name: "identical corrections",
+			corrections: []*Correction{
+				{Words: map[string]string{"teh": "the"}},
+				{Words: map[string]string{"teh": "the"}},
+			},
+		},
+		{
+			name: "different corrections in different languages",
+			corrections: []*Correction{
+				{Languages: []string{"go"}, Words: map[string]string{"fucn": "func"}},
+				{Languages: []string{"python"}, Words: map[string]string{"fucn": "def"}},
+			},
+		},
+		{
+			name: "different corrections in the same language",
+			corrections: []*Correction{
+				{Languages: []string{"go", "java"}, Words: map[string]string{"fucn": "func"}},
+				{Languages: []string{"python", "java"}, Words: map[string]string{"fucn": "def"}},
+			},
+			want: []string{
+				`typo "fucn" for language "java" is defined differently in corrections 0 and 1`,
+			},
+		},
+		{
+			name: "empty and global languages are the same",
+			corrections: []*Correction{
+				{Words: map[string]string{"teh": "the"}},
+				{Languages: []string{"*"}, Words: map[string]string{"teh": "tea"}},
+			},
+			want: []string{
+				`typo "teh" for language "*" is defined differently in corrections 0 and 1`,
+			},
+		},
+		{
+			name: "different replacement suffix",
+			corrections: []*Correction{
+				{Languages: []string{"go"}, Words: map[string]string{"fi": "if"}},
+				{Languages: []string{"go"}, Words: map[string]string{"fi": "if"}, ReplacementSuffix: " {"},
+			},
+			want: []string{
+				`typo "fi" for language "go" is defined differently in corrections 0 and 1`,
+			},
+		},
+		{
+			name: "disjoint break characters",
+			corrections: []*Correction{
+				{Words: map[string]string{"teh": "the"}, BreakCharacters: " "},
+				{Words: map[string]string{"teh": "tea"}, BreakCharacters: "."},
+			},
+		},
+		{
+			name: "overlapping break characters",
+			corrections: []*Correction{
+				{Words: map[string]string{"teh": "the"}, BreakCharacters: " ."},
+				{Words: map[string]string{"teh": "tea"}, BreakCharacters: "."},
+			},
+			want: []string{
+				`typo "teh" for language "*" is defined differently in corrections 0 and 1`,
+			},
+		},
+		{
+			name: "conflicts are sorted",
+			corrections: []*Correction{
+				{Languages: []string{"go", "java"}, Words: map[string]string{"b": "c", "a": "b"}},
+				{Languages: []string{"java", "go"}, Words: map[string]string{"a": "c", "b": "d"}},
+			},
+			want: []string{
+				`typo "a" for language "go" is defined differently in corrections 0 and 1`,
+				`typo "a" for language "java" is defined differently in corrections 0 and 1`,
+				`typo "b" for language "go" is defined differently in corrections 0 and 1`,
+				`typo "b" for language "java" is defined differently in corrections 0 and 1`,
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkCorrectionConflicts(test.corrections)
+			if len(test.want) == 0 {
+				if err != nil {
+					t.Errorf("checkCorrectionConflicts() returned error: %v", err)
+				}
+				return
+			}
+			if want := strings.Join(test.want, "\n"); err == nil || err.Error() != want {
+				t.Errorf("checkCorrectionConflicts() returned error %v; want %q", err, want)
+			}
+		})
+	}
+}
+
+func TestSplitCorrections(t *testing.T) {
+	corrections := []*Correction{
+		{Words: map[string]string{"teh": "the"}},
+		{Languages: []string{"go"}, Words: map[string]string{"fucn": "func"}},
+		{Languages: []string{"*"}, Words: map[string]string{"adn": "and"}},
+		{Languages: []string{"python", "*"}, Words: map[string]string{"slef": "self"}},
+		{Languages: []string{"java", "typescript"}, Words: map[string]string{"fucntion": "function"}},
+	}
+
+	global, languageSpecific := splitCorrections(corrections)
+	if want := compactJson([]*Correction{corrections[0], corrections[2], corrections[3]}); compactJson(global) != want {
+		t.Errorf("splitCorrections() returned global corrections %s; want %s", compactJson(global), want)
+	}
+	if want := compactJson([]*Correction{corrections[1], corrections[4]}); compactJson(languageSpecific) != want {
+		t.Errorf("splitCorrections() returned language-specific corrections %s; want %s", compactJson(languageSpecific), want)
+	}
+}
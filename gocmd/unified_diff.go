@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	unifiedDiffContext = 3
+)
+
+// unifiedDiff returns a unified diff (like `diff -u`) of the provided
+// contents. An empty string is returned if the contents are identical.
+func unifiedDiff(oldName, newName, oldContents, newContents string) string {
+	if oldContents == newContents {
+		return ""
+	}
+
+	a, b := splitDiffLines(oldContents), splitDiffLines(newContents)
+	edits := diffLines(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Group the edits into hunks with unifiedDiffContext lines of context.
+	for start := 0; start < len(edits); {
+		// Find the next change.
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+
+		hunkStart := start - unifiedDiffContext
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+
+		// Extend the hunk until there are more than 2*context unchanged lines.
+		end := start
+		for unchanged := 0; end < len(edits) && unchanged <= 2*unifiedDiffContext; end++ {
+			if edits[end].op == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		// Trim trailing context.
+		for end > start && edits[end-1].op == ' ' {
+			end--
+		}
+		hunkEnd := end + unifiedDiffContext
+		if hunkEnd > len(edits) {
+			hunkEnd = len(edits)
+		}
+
+		hunk := edits[hunkStart:hunkEnd]
+		var oldCount, newCount int
+		for _, e := range hunk {
+			if e.op != '+' {
+				oldCount++
+			}
+			if e.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(hunk[0].oldLine, oldCount), hunkRange(hunk[0].newLine, newCount))
+		for _, e := range hunk {
+			fmt.Fprintf(&sb, "%c%s\n", e.op, e.text)
+		}
+		start = hunkEnd
+	}
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	// Ranges are 1-indexed, and empty ranges refer to the line before the hunk.
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitDiffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+type diffEdit struct {
+	// op is one of ' ', '-', or '+'.
+	op   rune
+	text string
+	// oldLine and newLine are the (0-indexed) line numbers in the old and new
+	// contents where this edit occurs.
+	oldLine int
+	newLine int
+}
+
+// diffLines returns the edits needed to transform a into b. This uses the
+// linear space variant of the Myers diff algorithm (see section 4b of "An
+// O(ND) Difference Algorithm and Its Variations"), so large files don't
+// require storing every round of the search.
+func diffLines(a, b []string) []*diffEdit {
+	ld := &lineDiff{a: a, b: b}
+	ld.diff(0, len(a), 0, len(b))
+	return ld.edits
+}
+
+type lineDiff struct {
+	a     []string
+	b     []string
+	edits []*diffEdit
+}
+
+// diff appends the edits that transform a[aLo:aHi] into b[bLo:bHi].
+func (ld *lineDiff) diff(aLo, aHi, bLo, bHi int) {
+	// Common prefix
+	for aLo < aHi && bLo < bHi && ld.a[aLo] == ld.b[bLo] {
+		ld.edits = append(ld.edits, &diffEdit{' ', ld.a[aLo], aLo, bLo})
+		aLo++
+		bLo++
+	}
+	// Common suffix (added after the middle edits)
+	var suffix int
+	for aHi-suffix > aLo && bHi-suffix > bLo && ld.a[aHi-suffix-1] == ld.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			ld.edits = append(ld.edits, &diffEdit{'+', ld.b[y], aLo, y})
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			ld.edits = append(ld.edits, &diffEdit{'-', ld.a[x], x, bLo})
+		}
+	default:
+		// Both ranges are non-empty and differ at both ends, so there are at
+		// least two edits and each side of the middle snake has fewer edits.
+		x, y, u, v := ld.middleSnake(aLo, aHi, bLo, bHi)
+		ld.diff(aLo, x, bLo, y)
+		for ; x < u; x, y = x+1, y+1 {
+			ld.edits = append(ld.edits, &diffEdit{' ', ld.a[x], x, y})
+		}
+		ld.diff(u, aHi, v, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		ld.edits = append(ld.edits, &diffEdit{' ', ld.a[aHi+i], aHi + i, bHi + i})
+	}
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the middle snake of
+// an optimal path from (aLo, bLo) to (aHi, bHi).
+func (ld *lineDiff) middleSnake(aLo, aHi, bLo, bHi int) (int, int, int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	maxD := (n+m+1)/2 + 1
+	offset := maxD + 1
+	// forward[k] is the furthest x on diagonal k (x - y = k) from the start and
+	// backward[k] is the furthest x on diagonal k from the end (in reversed
+	// coordinates).
+	forward, backward := make([]int, 2*maxD+3), make([]int, 2*maxD+3)
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && ld.a[aLo+x] == ld.b[bLo+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			if rk := delta - k; delta%2 != 0 && rk >= -(d-1) && rk <= d-1 && x+backward[offset+rk] >= n {
+				return aLo + startX, bLo + startY, aLo + x, bLo + y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && ld.a[aHi-x-1] == ld.b[bHi-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if fk := delta - k; delta%2 == 0 && fk >= -d && fk <= d && x+forward[offset+fk] >= n {
+				return aHi - x, bHi - y, aHi - startX, bHi - startY
+			}
+		}
+	}
+	panic("no middle snake found")
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "empty contents",
+		},
+		{
+			name: "identical contents",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+		},
+		{
+			name: "insert into empty file",
+			new:  "a\nb\n",
+			want: strings.Join([]string{
+				"--- old",
+				"+++ new",
+				"@@ -0,0 +1,2 @@",
+				"+a",
+				"+b",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "delete entire file",
+			old:  "a\nb\n",
+			want: strings.Join([]string{
+				"--- old",
+				"+++ new",
+				"@@ -1,2 +0,0 @@",
+				"-a",
+				"-b",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "insert only",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n",
+			new:  "1\n2\n3\n4\nnew\n5\n6\n7\n8\n",
+			want: strings.Join([]string{
+				"--- old",
+				"+++ new",
+				"@@ -2,6 +2,7 @@",
+				" 2",
+				" 3",
+				" 4",
+				"+new",
+				" 5",
+				" 6",
+				" 7",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "delete only",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n",
+			new:  "1\n2\n3\n5\n6\n7\n8\n",
+			want: strings.Join([]string{
+				"--- old",
+				"+++ new",
+				"@@ -1,7 +1,6 @@",
+				" 1",
+				" 2",
+				" 3",
+				"-4",
+				" 5",
+				" 6",
+				" 7",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "change at the start of the file",
+			old:  "a\n2\n3\n4\n5\n",
+			new:  "b\n2\n3\n4\n5\n",
+			want: strings.Join([]string{
+				"--- old",
+				"+++ new",
+				"@@ -1,4 +1,4 @@",
+				"-a",
+				"+b",
+				" 2",
+				" 3",
+				" 4",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "nearby changes share a hunk",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:  "1\n2\nthree\n4\n5\n6\n7\n8\nnine\n10\n",
+			want: strings.Join([]string{
+				"--- old",
+				"+++ new",
+				"@@ -1,10 +1,10 @@",
+				" 1",
+				" 2",
+				"-3",
+				"+three",
+				" 4",
+				" 5",
+				" 6",
+				" 7",
+				" 8",
+				"-9",
+				"+nine",
+				" 10",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "distant changes are separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want: strings.Join([]string{
+				"--- old",
+				"+++ new",
+				"@@ -1,4 +1,4 @@",
+				"-1",
+				"+one",
+				" 2",
+				" 3",
+				" 4",
+				"@@ -9,4 +9,4 @@",
+				" 9",
+				" 10",
+				" 11",
+				"-12",
+				"+twelve",
+				"",
+			}, "\n"),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := unifiedDiff("old", "new", test.old, test.new); got != test.want {
+				t.Errorf("unifiedDiff() returned:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		var lines []string
+		for i := r.Intn(12); i > 0; i-- {
+			lines = append(lines, string(rune('a'+r.Intn(3))))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		t.Run(fmt.Sprintf("%q to %q", a, b), func(t *testing.T) {
+			edits := diffLines(a, b)
+
+			var gotA, gotB []string
+			var changes int
+			for _, e := range edits {
+				if e.op != '+' {
+					if e.oldLine != len(gotA) {
+						t.Errorf("diffLines() returned edit %c%s with oldLine %d; want %d", e.op, e.text, e.oldLine, len(gotA))
+					}
+					gotA = append(gotA, e.text)
+				}
+				if e.op != '-' {
+					if e.newLine != len(gotB) {
+						t.Errorf("diffLines() returned edit %c%s with newLine %d; want %d", e.op, e.text, e.newLine, len(gotB))
+					}
+					gotB = append(gotB, e.text)
+				}
+				if e.op != ' ' {
+					changes++
+				}
+			}
+
+			if strings.Join(gotA, "\n") != strings.Join(a, "\n") {
+				t.Errorf("diffLines() old lines are %q; want %q", gotA, a)
+			}
+			if strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+				t.Errorf("diffLines() new lines are %q; want %q", gotB, b)
+			}
+			if want := len(a) + len(b) - 2*longestCommonSubsequence(a, b); changes != want {
+				t.Errorf("diffLines() returned %d changes; want %d", changes, want)
+			}
+		})
+	}
+}
+
+func longestCommonSubsequence(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] > lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	return lcs[0][0]
+}
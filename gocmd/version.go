@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// versionFile is the file (relative to the groog root directory) that
+	// contains the extension version. If it doesn't exist, then the version is
+	// determined by the latest `v*` git tag.
+	versionFile      = "VERSION"
+	versionTagPrefix = "v"
+)
+
+// versionSource is where the extension version is stored.
+type versionSource interface {
+	// read returns the current version.
+	read() (*Version, error)
+	// write records the new version and returns a message describing what
+	// was done (or what still needs to be done) to record it.
+	write(v *Version) (string, error)
+	// String returns a description of the source.
+	String() string
+}
+
+// groogVersionSource returns the version file source if the version file
+// exists, and the git tag source otherwise.
+func groogVersionSource(root string) versionSource {
+	if _, err := os.Stat(filepath.Join(root, versionFile)); err == nil {
+		return &fileVersionSource{filepath.Join(root, versionFile)}
+	}
+	return &gitTagVersionSource{root}
+}
+
+// checkRecordedVersion returns an error if the provided package.json version
+// isn't the current version of the source (e.g. if the release commit wasn't
+// tagged after running `vs-package update`).
+func checkRecordedVersion(source versionSource, packageVersion string) error {
+	v, err := source.read()
+	if err != nil {
+		return err
+	}
+	if v.String() == packageVersion {
+		return nil
+	}
+
+	msg := fmt.Sprintf("package.json version %s doesn't match the current version %s (%s)", packageVersion, v, source)
+	if gvs, ok := source.(*gitTagVersionSource); ok {
+		if pv, err := parseVersion(packageVersion); err == nil && pv.compare(v) > 0 {
+			msg += fmt.Sprintf("; tag the release commit with `%s`", gvs.tagCommand(pv))
+		}
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// currentVersion returns the current version of the extension.
+func currentVersion(root string) (*Version, error) {
+	return groogVersionSource(root).read()
+}
+
+type fileVersionSource struct {
+	filename string
+}
+
+func (fvs *fileVersionSource) read() (*Version, error) {
+	b, err := os.ReadFile(fvs.filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version file: %v", err)
+	}
+	return parseVersion(strings.TrimSpace(string(b)))
+}
+
+func (fvs *fileVersionSource) write(v *Version) (string, error) {
+	if err := os.WriteFile(fvs.filename, []byte(v.String()+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write version file: %v", err)
+	}
+	return fmt.Sprintf("Successfully updated to new version: %s (%s)", v, fvs), nil
+}
+
+func (fvs *fileVersionSource) String() string {
+	return fvs.filename
+}
+
+type gitTagVersionSource struct {
+	root string
+}
+
+func (gvs *gitTagVersionSource) read() (*Version, error) {
+	// Only consider tags that are reachable from HEAD so that releases on other
+	// branches don't affect the version.
+	out, err := exec.Command("git", "-C", gvs.root, "tag", "--list", "--merged", "HEAD", versionTagPrefix+"*").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list git tags: %v", err)
+	}
+
+	var latest *Version
+	for _, tag := range strings.Fields(string(out)) {
+		// Ignore tags that aren't versions (e.g. `vscode-test`).
+		v, err := parseVersion(strings.TrimPrefix(tag, versionTagPrefix))
+		if err != nil {
+			continue
+		}
+		if latest == nil || v.compare(latest) > 0 {
+			latest = v
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("no %s file or %s* git tags reachable from HEAD found", versionFile, versionTagPrefix)
+	}
+	return latest, nil
+}
+
+// write doesn't create the tag, since the current commit doesn't include the
+// regenerated files yet. Instead, it returns instructions for tagging the
+// release commit (`vs-package --check` fails until the tag is created).
+func (gvs *gitTagVersionSource) write(v *Version) (string, error) {
+	return strings.Join([]string{
+		fmt.Sprintf("Generated files for new version: %s (%s)", v, gvs),
+		"Commit the changes and then tag the release commit:",
+		"  " + gvs.tagCommand(v),
+	}, "\n"), nil
+}
+
+// tagCommand returns the command for tagging the release commit.
+func (gvs *gitTagVersionSource) tagCommand(v *Version) string {
+	tag := versionTagPrefix + v.String()
+	return fmt.Sprintf("git -C %s tag -a %s -m %q", gvs.root, tag, "Release "+tag)
+}
+
+func (gvs *gitTagVersionSource) String() string {
+	return fmt.Sprintf("git tags (%s*)", versionTagPrefix)
+}
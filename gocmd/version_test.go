@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileVersionSource(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, versionFile)
+	fvs := &fileVersionSource{filename}
+
+	if _, err := fvs.read(); err == nil || !strings.HasPrefix(err.Error(), "failed to read version file: ") {
+		t.Errorf("read() returned error %v; want a read failure", err)
+	}
+
+	if err := os.WriteFile(filename, []byte("1.2\n"), 0644); err != nil {
+		t.Fatalf("failed to write version file: %v", err)
+	}
+	if _, err := fvs.read(); err == nil {
+		t.Errorf("read() of an invalid version returned nil error")
+	}
+
+	if err := os.WriteFile(filename, []byte(" 1.2.3\n"), 0644); err != nil {
+		t.Fatalf("failed to write version file: %v", err)
+	}
+	v, err := fvs.read()
+	if err != nil {
+		t.Fatalf("read() returned error: %v", err)
+	}
+	if v.String() != "1.2.3" {
+		t.Errorf("read() returned %s; want 1.2.3", v)
+	}
+
+	nv, err := v.bump(minorVersion, "")
+	if err != nil {
+		t.Fatalf("bump() returned error: %v", err)
+	}
+	msg, err := fvs.write(nv)
+	if err != nil {
+		t.Fatalf("write() returned error: %v", err)
+	}
+	if want := "Successfully updated to new version: 1.3.0 (" + filename + ")"; msg != want {
+		t.Errorf("write() returned %q; want %q", msg, want)
+	}
+	if b, err := os.ReadFile(filename); err != nil || string(b) != "1.3.0\n" {
+		t.Errorf("write() wrote (%q, %v); want (%q, nil)", b, err, "1.3.0\n")
+	}
+
+	if err := checkRecordedVersion(fvs, "1.3.0"); err != nil {
+		t.Errorf("checkRecordedVersion() returned error: %v", err)
+	}
+	if err, want := checkRecordedVersion(fvs, "1.2.3"), "package.json version 1.2.3 doesn't match the current version 1.3.0 ("+filename+")"; err == nil || err.Error() != want {
+		t.Errorf("checkRecordedVersion() returned error %v; want %q", err, want)
+	}
+}
+
+func TestGitTagVersionSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	gvs := &gitTagVersionSource{dir}
+
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+	if _, err := gvs.read(); err == nil || err.Error() != "no VERSION file or v* git tags reachable from HEAD found" {
+		t.Errorf("read() without tags returned error %v", err)
+	}
+
+	git("tag", "v1.0.0")
+	git("tag", "vscode-test")
+	git("commit", "-q", "--allow-empty", "-m", "second")
+	git("tag", "v1.2.0")
+	git("tag", "v1.10.0-beta")
+
+	// Tags on other branches aren't reachable from HEAD.
+	git("checkout", "-q", "-b", "other")
+	git("commit", "-q", "--allow-empty", "-m", "other")
+	git("tag", "v2.0.0")
+	git("checkout", "-q", "main")
+
+	v, err := gvs.read()
+	if err != nil {
+		t.Fatalf("read() returned error: %v", err)
+	}
+	if v.String() != "1.10.0-beta" {
+		t.Errorf("read() returned %s; want 1.10.0-beta", v)
+	}
+
+	git("checkout", "-q", "other")
+	if v, err := gvs.read(); err != nil || v.String() != "2.0.0" {
+		t.Errorf("read() on other branch returned (%v, %v); want (2.0.0, nil)", v, err)
+	}
+	git("checkout", "-q", "main")
+
+	// write doesn't create the tag.
+	nv, err := parseVersion("1.10.0")
+	if err != nil {
+		t.Fatalf("parseVersion() returned error: %v", err)
+	}
+	msg, err := gvs.write(nv)
+	if err != nil {
+		t.Fatalf("write() returned error: %v", err)
+	}
+	wantMsg := strings.Join([]string{
+		"Generated files for new version: 1.10.0 (git tags (v*))",
+		"Commit the changes and then tag the release commit:",
+		"  git -C " + dir + ` tag -a v1.10.0 -m "Release v1.10.0"`,
+	}, "\n")
+	if msg != wantMsg {
+		t.Errorf("write() returned:\n%s\nwant:\n%s", msg, wantMsg)
+	}
+	if v, err := gvs.read(); err != nil || v.String() != "1.10.0-beta" {
+		t.Errorf("read() after write() returned (%v, %v); want (1.10.0-beta, nil)", v, err)
+	}
+
+	// The check fails until the release commit is tagged.
+	wantErr := "package.json version 1.10.0 doesn't match the current version 1.10.0-beta (git tags (v*)); tag the release commit with `git -C " + dir + " tag -a v1.10.0 -m \"Release v1.10.0\"`"
+	if err := checkRecordedVersion(gvs, "1.10.0"); err == nil || err.Error() != wantErr {
+		t.Errorf("checkRecordedVersion() returned error %v; want %q", err, wantErr)
+	}
+	git("tag", "-a", "v1.10.0", "-m", "Release v1.10.0")
+	if err := checkRecordedVersion(gvs, "1.10.0"); err != nil {
+		t.Errorf("checkRecordedVersion() returned error: %v", err)
+	}
+}
+
+func TestGroogVersionSource(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok := groogVersionSource(dir).(*gitTagVersionSource); !ok {
+		t.Errorf("groogVersionSource() without a version file returned %T; want *gitTagVersionSource", groogVersionSource(dir))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, versionFile), []byte("1.0.0\n"), 0644); err != nil {
+		t.Fatalf("failed to write version file: %v", err)
+	}
+	if _, ok := groogVersionSource(dir).(*fileVersionSource); !ok {
+		t.Errorf("groogVersionSource() with a version file returned %T; want *fileVersionSource", groogVersionSource(dir))
+	}
+}
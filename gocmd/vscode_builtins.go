@@ -0,0 +1,216 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// vscodeEngine is the VS Code version range that groog supports.
+	vscodeEngine = "^1.96.0"
+
+	// vscodeBuiltinsFile is the catalog of VS Code's built-in command IDs and
+	// context keys (relative to the gocmd directory). It is generated from VS
+	// Code's default keybindings file by `vs-package builtins`.
+	vscodeBuiltinsFile = "vscode_builtins.json"
+)
+
+var (
+	//go:embed vscode_builtins.json
+	vscodeBuiltinsJSON []byte
+
+	// availableCommandRegex matches the commands without default keybindings
+	// that are listed in comments at the end of the default keybindings file.
+	availableCommandRegex = regexp.MustCompile(`(?m)^\s*//\s*-\s*(\S+)\s*$`)
+
+	// ownedContextKeyPrefixes are the prefixes of context keys that aren't
+	// provided by VS Code itself.
+	ownedContextKeyPrefixes = []string{
+		"groog.",
+		// Settings values (e.g. `config.editor.tabSize`).
+		"config.",
+	}
+)
+
+// VSCodeBuiltins is a catalog of the built-in command IDs and context keys
+// for a version of VS Code.
+type VSCodeBuiltins struct {
+	Version     string   `json:"version"`
+	Commands    []string `json:"commands"`
+	ContextKeys []string `json:"contextKeys"`
+}
+
+func loadVSCodeBuiltins() (*VSCodeBuiltins, error) {
+	var vb VSCodeBuiltins
+	if err := json.Unmarshal(vscodeBuiltinsJSON, &vb); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %v", vscodeBuiltinsFile, err)
+	}
+	return &vb, nil
+}
+
+// vscodeEngineVersion returns the minimum VS Code version in vscodeEngine.
+func vscodeEngineVersion() string {
+	return strings.TrimLeft(vscodeEngine, "^~>=")
+}
+
+// parseDefaultKeybindings builds a catalog from the contents of VS Code's
+// default keybindings file (from the `Preferences: Open Default Keyboard
+// Shortcuts (JSON)` command).
+func parseDefaultKeybindings(contents []byte, version string) (*VSCodeBuiltins, error) {
+	kbs, err := unmarshalDefaultKeybindings(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := map[string]bool{}
+	contextKeys := map[string]bool{}
+	for _, kb := range kbs {
+		commands[strings.TrimPrefix(kb.Command, "-")] = true
+		keys, err := whenContextKeys(kb.When)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			contextKeys[k] = true
+		}
+	}
+
+	// Commands without default keybindings are listed in the trailing comments.
+	for _, m := range availableCommandRegex.FindAllSubmatch(contents, -1) {
+		commands[string(m[1])] = true
+	}
+
+	return &VSCodeBuiltins{
+		Version:     version,
+		Commands:    sortedKeys(commands),
+		ContextKeys: sortedKeys(contextKeys),
+	}, nil
+}
+
+// unmarshalDefaultKeybindings parses a keybindings file (which may contain
+// comments).
+func unmarshalDefaultKeybindings(contents []byte) ([]*Keybinding, error) {
+	var kbs []*Keybinding
+	if err := json.Unmarshal(stripJSONComments(contents), &kbs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal default keybindings: %v", err)
+	}
+	return kbs, nil
+}
+
+// stripJSONComments removes `//` and `/* */` comments (outside of strings) from
+// the json contents.
+func stripJSONComments(contents []byte) []byte {
+	var r []byte
+	for i := 0; i < len(contents); i++ {
+		c := contents[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for ; j < len(contents) && contents[j] != '"'; j++ {
+				if contents[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(contents) {
+				j = len(contents) - 1
+			}
+			r = append(r, contents[i:j+1]...)
+			i = j
+		case c == '/' && i+1 < len(contents) && contents[i+1] == '/':
+			for i < len(contents) && contents[i] != '\n' {
+				i++
+			}
+			if i < len(contents) {
+				r = append(r, '\n')
+			}
+		case c == '/' && i+1 < len(contents) && contents[i+1] == '*':
+			end := strings.Index(string(contents[i+2:]), "*/")
+			if end < 0 {
+				return r
+			}
+			i += end + 3
+		default:
+			r = append(r, c)
+		}
+	}
+	return r
+}
+
+// builtinWarnings returns warnings for the commands and context keys used by
+// the keybindings that aren't in the VS Code catalog. Commands from groog and
+// companion extensions are ignored.
+func builtinWarnings(kbs []*Keybinding, vb *VSCodeBuiltins) ([]string, error) {
+	if len(vb.Commands) == 0 {
+		return []string{fmt.Sprintf("%s is empty; run `vs-package builtins DEFAULT_KEYBINDINGS_JSON %s` with VS Code %s's default keybindings file to generate it", vscodeBuiltinsFile, vscodeEngineVersion(), vscodeEngineVersion())}, nil
+	}
+
+	var warnings []string
+	if v := vscodeEngineVersion(); vb.Version != v {
+		warnings = append(warnings, fmt.Sprintf("%s is for VS Code %s, but the engine version is %s; run `vs-package builtins` to refresh it", vscodeBuiltinsFile, vb.Version, v))
+	}
+
+	knownCommands := map[string]bool{}
+	for _, c := range vb.Commands {
+		knownCommands[c] = true
+	}
+	knownKeys := map[string]bool{}
+	for _, k := range vb.ContextKeys {
+		knownKeys[k] = true
+	}
+
+	unknownCommands := map[string][]string{}
+	unknownKeys := map[string][]string{}
+	for _, kb := range kbs {
+		for _, cmd := range keybindingCommands(kb) {
+			cmd = strings.TrimPrefix(cmd, "-")
+			if strings.HasPrefix(cmd, "groog.") || commandExtension(cmd) != nil || knownCommands[cmd] {
+				continue
+			}
+			unknownCommands[cmd] = append(unknownCommands[cmd], kb.Key)
+		}
+
+		keys, err := whenContextKeys(kb.When)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if knownKeys[k] || ownedContextKey(k) {
+				continue
+			}
+			unknownKeys[k] = append(unknownKeys[k], kb.Key)
+		}
+	}
+
+	for _, cmd := range sortedKeys(unknownCommands) {
+		warnings = append(warnings, fmt.Sprintf("unknown VS Code command %q (bound to %s)", cmd, uniqueKeys(unknownCommands[cmd])))
+	}
+	for _, k := range sortedKeys(unknownKeys) {
+		warnings = append(warnings, fmt.Sprintf("unknown VS Code context key %q (used by %s)", k, uniqueKeys(unknownKeys[k])))
+	}
+	return warnings, nil
+}
+
+func ownedContextKey(k string) bool {
+	for _, prefix := range ownedContextKeyPrefixes {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	for _, ce := range companionExtensions {
+		if strings.HasPrefix(k, ce.Namespace+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueKeys(keys []string) string {
+	m := map[string]bool{}
+	for _, k := range keys {
+		m[k] = true
+	}
+	return fmt.Sprintf("`%s`", strings.Join(sortedKeys(m), "`, `"))
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDefaultKeybindings(t *testing.T) {
+	contents, err := os.ReadFile(filepath.Join("testdata", "vscode_builtins", "keybindings.json"))
+	if err != nil {
+		t.Fatalf("failed to read default keybindings: %v", err)
+	}
+
+	got, err := parseDefaultKeybindings(contents, "1.96.0")
+	if err != nil {
+		t.Fatalf("parseDefaultKeybindings() returned error: %v", err)
+	}
+
+	want := &VSCodeBuiltins{
+		Version: "1.96.0",
+		Commands: []string{
+			"editor.action.addCommentLine",
+			"editor.action.blockComment",
+			"editor.action.clipboardCopyAction",
+			"editor.action.commentLine",
+			"editor.action.formatDocument.none",
+			"workbench.action.debug.start",
+			"workbench.action.exitZenMode",
+			"workbench.action.zoomIn",
+		},
+		ContextKeys: []string{
+			"debugSchemes",
+			"debugState",
+			"debuggersAvailable",
+			"editorLangId",
+			"editorReadonly",
+			"editorTextFocus",
+			"inZenMode",
+			"resourceExtname",
+			"resourceScheme",
+		},
+	}
+	if compactJson(got) != compactJson(want) {
+		t.Errorf("parseDefaultKeybindings() returned %s; want %s", compactJson(got), compactJson(want))
+	}
+}
+
+func TestParseDefaultKeybindingsErrors(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{
+			name:     "invalid json",
+			contents: `[{"key": "ctrl+c",}]`,
+			want:     "failed to unmarshal default keybindings",
+		},
+		{
+			name:     "invalid when clause",
+			contents: `[{"key": "ctrl+c", "command": "copy", "when": "a && ("}]`,
+			want:     `failed to parse when clause "a && ("`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseDefaultKeybindings([]byte(test.contents), "1.96.0")
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("parseDefaultKeybindings() returned error %v; want error containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestStripJSONComments(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{
+			name:     "no comments",
+			contents: `{"a": 1}`,
+			want:     `{"a": 1}`,
+		},
+		{
+			name:     "line comments",
+			contents: "// header\n{\"a\": 1} // trailing\n",
+			want:     "\n{\"a\": 1} \n",
+		},
+		{
+			name:     "line comment at the end of the file",
+			contents: "{} // trailing",
+			want:     "{} ",
+		},
+		{
+			name:     "block comments",
+			contents: "{/* one */\"a\": /* two\nlines */1}",
+			want:     "{\"a\": 1}",
+		},
+		{
+			name:     "unterminated block comment",
+			contents: "{} /* oops",
+			want:     "{} ",
+		},
+		{
+			name:     "comment characters in strings",
+			contents: `{"url": "https://example.com", "glob": "/* not a comment */"}`,
+			want:     `{"url": "https://example.com", "glob": "/* not a comment */"}`,
+		},
+		{
+			name:     "escaped quotes in strings",
+			contents: `{"a": "quote \" // still a string"} // comment`,
+			want:     `{"a": "quote \" // still a string"} `,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(stripJSONComments([]byte(test.contents))); got != test.want {
+				t.Errorf("stripJSONComments(%q) returned %q; want %q", test.contents, got, test.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinWarnings(t *testing.T) {
+	kbs := []*Keybinding{
+		{Key: "ctrl+c", Command: "editor.action.clipboardCopyAction", When: "editorTextFocus"},
+		{Key: "ctrl+v", Command: "editor.action.pasteFromTheFuture", When: "editorTextFocus && unknownKey"},
+		{Key: "ctrl+x", Command: "editor.action.pasteFromTheFuture"},
+		{Key: "ctrl+g", Command: "groog.ctrlG", When: "groog.context.findMode && config.editor.tabSize == 2"},
+		{Key: "ctrl+b", Command: "gitlens.toggleLineBlame", When: "gitlens.enabled"},
+		{Key: "ctrl+z", Command: "-undo"},
+	}
+
+	for _, test := range []struct {
+		name string
+		vb   *VSCodeBuiltins
+		want []string
+	}{
+		{
+			name: "empty catalog",
+			vb:   &VSCodeBuiltins{},
+			want: []string{
+				"vscode_builtins.json is empty; run `vs-package builtins DEFAULT_KEYBINDINGS_JSON 1.96.0` with VS Code 1.96.0's default keybindings file to generate it",
+			},
+		},
+		{
+			name: "unknown commands and context keys",
+			vb: &VSCodeBuiltins{
+				Version:     vscodeEngineVersion(),
+				Commands:    []string{"editor.action.clipboardCopyAction", "undo"},
+				ContextKeys: []string{"editorTextFocus"},
+			},
+			want: []string{
+				"unknown VS Code command \"editor.action.pasteFromTheFuture\" (bound to `ctrl+v`, `ctrl+x`)",
+				"unknown VS Code context key \"unknownKey\" (used by `ctrl+v`)",
+			},
+		},
+		{
+			name: "outdated catalog",
+			vb: &VSCodeBuiltins{
+				Version:     "1.0.0",
+				Commands:    []string{"editor.action.clipboardCopyAction", "editor.action.pasteFromTheFuture", "undo"},
+				ContextKeys: []string{"editorTextFocus", "unknownKey"},
+			},
+			want: []string{
+				"vscode_builtins.json is for VS Code 1.0.0, but the engine version is " + vscodeEngineVersion() + "; run `vs-package builtins` to refresh it",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := builtinWarnings(kbs, test.vb)
+			if err != nil {
+				t.Fatalf("builtinWarnings() returned error: %v", err)
+			}
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("builtinWarnings() returned:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
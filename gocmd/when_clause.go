@@ -0,0 +1,514 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// See the following link for details on when clause syntax:
+// https://code.visualstudio.com/api/references/when-clause-contexts
+
+// whenExpr is a parsed when clause.
+type whenExpr interface {
+	// keys appends the context keys referenced by the expression.
+	keys(m map[string]bool)
+	// evaluate returns whether the expression is true for the context values.
+	// Any parts of the expression that can't be evaluated accurately are
+	// reported with warn.
+	evaluate(ctx whenContextValues, warn func(string)) bool
+	// String returns the expression as a when clause with only the necessary
+	// parentheses (nested `&&` and `||` operations are flattened).
+	String() string
+}
+
+// whenContextValues maps context keys to their values. Missing keys are
+// undefined (and therefore falsy).
+type whenContextValues map[string]interface{}
+
+// parseWhenContextValues parses a comma-separated list of `key=value`
+// assignments. Boolean and numeric values are converted to bools and numbers
+// and a key without a value is set to true.
+func parseWhenContextValues(s string) (whenContextValues, error) {
+	ctx := whenContextValues{}
+	for _, assignment := range strings.Split(s, ",") {
+		assignment = strings.TrimSpace(assignment)
+		if assignment == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(assignment, "=")
+		k = strings.TrimSpace(k)
+		if k == "" {
+			return nil, fmt.Errorf("invalid context assignment %q", assignment)
+		}
+		if !ok {
+			ctx[k] = true
+			continue
+		}
+		ctx[k] = parseWhenValue(strings.TrimSpace(v))
+	}
+	return ctx, nil
+}
+
+func parseWhenValue(v string) interface{} {
+	if b, err := strconv.ParseBool(v); err == nil && (v == "true" || v == "false") {
+		return b
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return f
+	}
+	return v
+}
+
+func whenTruthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	}
+	return true
+}
+
+func whenValueString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+type whenConst struct {
+	value bool
+}
+
+type whenKey struct {
+	key string
+}
+
+type whenNot struct {
+	expr whenExpr
+}
+
+type whenAnd struct {
+	parts []whenExpr
+}
+
+type whenOr struct {
+	parts []whenExpr
+}
+
+// whenCompare is a comparison of a context key with a value (or with another
+// context key for the `in` and `not in` operators).
+type whenCompare struct {
+	key   string
+	op    string
+	value string
+}
+
+func (wc *whenConst) keys(m map[string]bool) {}
+
+func (wk *whenKey) keys(m map[string]bool) {
+	m[wk.key] = true
+}
+
+func (wn *whenNot) keys(m map[string]bool) {
+	wn.expr.keys(m)
+}
+
+func (wa *whenAnd) keys(m map[string]bool) {
+	for _, p := range wa.parts {
+		p.keys(m)
+	}
+}
+
+func (wo *whenOr) keys(m map[string]bool) {
+	for _, p := range wo.parts {
+		p.keys(m)
+	}
+}
+
+func (wc *whenCompare) keys(m map[string]bool) {
+	m[wc.key] = true
+	if wc.op == "in" || wc.op == "not in" {
+		m[wc.value] = true
+	}
+}
+
+func (wc *whenConst) evaluate(ctx whenContextValues, warn func(string)) bool {
+	return wc.value
+}
+
+func (wk *whenKey) evaluate(ctx whenContextValues, warn func(string)) bool {
+	return whenTruthy(ctx[wk.key])
+}
+
+func (wn *whenNot) evaluate(ctx whenContextValues, warn func(string)) bool {
+	return !wn.expr.evaluate(ctx, warn)
+}
+
+func (wa *whenAnd) evaluate(ctx whenContextValues, warn func(string)) bool {
+	for _, p := range wa.parts {
+		if !p.evaluate(ctx, warn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (wo *whenOr) evaluate(ctx whenContextValues, warn func(string)) bool {
+	for _, p := range wo.parts {
+		if p.evaluate(ctx, warn) {
+			return true
+		}
+	}
+	return false
+}
+
+func (wc *whenCompare) evaluate(ctx whenContextValues, warn func(string)) bool {
+	v, ok := ctx[wc.key]
+	switch wc.op {
+	case "==":
+		return ok && whenValueString(v) == wc.value
+	case "!=":
+		return !ok || whenValueString(v) != wc.value
+	case "=~":
+		r, err := parseWhenRegex(wc.value)
+		return ok && err == nil && r.MatchString(whenValueString(v))
+	case "<", "<=", ">", ">=":
+		l, lErr := strconv.ParseFloat(whenValueString(v), 64)
+		r, rErr := strconv.ParseFloat(wc.value, 64)
+		if !ok || lErr != nil || rErr != nil {
+			return false
+		}
+		switch wc.op {
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		}
+		return l >= r
+	case "in", "not in":
+		// Only scalar context values are supported, so the right-hand side is
+		// never a list or object.
+		r := wc.op == "not in"
+		warn(fmt.Sprintf("`%s %s %s` is treated as %v since array and object context values aren't supported", wc.key, wc.op, wc.value, r))
+		return r
+	}
+	return false
+}
+
+func (wc *whenConst) String() string {
+	return strconv.FormatBool(wc.value)
+}
+
+func (wk *whenKey) String() string {
+	return wk.key
+}
+
+func (wn *whenNot) String() string {
+	switch wn.expr.(type) {
+	case *whenAnd, *whenOr, *whenCompare:
+		return fmt.Sprintf("!(%s)", wn.expr)
+	}
+	return fmt.Sprintf("!%s", wn.expr)
+}
+
+func (wa *whenAnd) String() string {
+	var parts []string
+	for _, p := range wa.parts {
+		if _, ok := p.(*whenOr); ok {
+			parts = append(parts, fmt.Sprintf("(%s)", p))
+		} else {
+			parts = append(parts, p.String())
+		}
+	}
+	return strings.Join(parts, " && ")
+}
+
+func (wo *whenOr) String() string {
+	var parts []string
+	for _, p := range wo.parts {
+		parts = append(parts, p.String())
+	}
+	return strings.Join(parts, " || ")
+}
+
+func (wc *whenCompare) String() string {
+	return fmt.Sprintf("%s %s %s", wc.key, wc.op, wc.value)
+}
+
+// normalizeWhenClause returns the when clause with consistent spacing and
+// parentheses. When clauses that can't be parsed are returned as is.
+func normalizeWhenClause(when string) string {
+	expr, err := parseWhenClause(when)
+	if err != nil {
+		return when
+	}
+	return expr.String()
+}
+
+// parseWhenRegex converts a javascript regex literal (e.g. `/^abc$/i`) into a go
+// regex.
+func parseWhenRegex(literal string) (*regexp.Regexp, error) {
+	end := strings.LastIndex(literal, "/")
+	if !strings.HasPrefix(literal, "/") || end <= 0 {
+		return nil, fmt.Errorf("invalid regex literal %q", literal)
+	}
+	pattern, flags := literal[1:end], literal[end+1:]
+	if strings.Contains(flags, "i") {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// whenContextKeys returns the (sorted) context keys referenced by the when
+// clause.
+func whenContextKeys(when string) ([]string, error) {
+	expr, err := parseWhenClause(when)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]bool{}
+	expr.keys(m)
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// parseWhenClause parses the when clause. An empty when clause is always true.
+func parseWhenClause(when string) (whenExpr, error) {
+	tokens, err := tokenizeWhenClause(when)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return &whenConst{true}, nil
+	}
+
+	wp := &whenParser{tokens: tokens}
+	expr, err := wp.or()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse when clause %q: %v", when, err)
+	}
+	if !wp.done() {
+		return nil, fmt.Errorf("failed to parse when clause %q: unexpected token %q", when, wp.peek().text)
+	}
+	return expr, nil
+}
+
+type whenTokenType int
+
+const (
+	whenOperator whenTokenType = iota
+	whenWord
+	whenString
+	whenRegex
+)
+
+type whenToken struct {
+	tokenType whenTokenType
+	text      string
+}
+
+var (
+	// whenOperators are ordered so that longer operators are matched first.
+	whenOperators = []string{"===", "!==", "==", "!=", "=~", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"}
+
+	whenComparisons = map[string]string{
+		"==":  "==",
+		"===": "==",
+		"!=":  "!=",
+		"!==": "!=",
+		"=~":  "=~",
+		"<":   "<",
+		"<=":  "<=",
+		">":   ">",
+		">=":  ">=",
+	}
+)
+
+func tokenizeWhenClause(when string) ([]*whenToken, error) {
+	var tokens []*whenToken
+	for i := 0; i < len(when); {
+		c := when[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			continue
+		case c == '\'':
+			end := strings.IndexByte(when[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in when clause %q", when)
+			}
+			tokens = append(tokens, &whenToken{whenString, when[i+1 : i+1+end]})
+			i += end + 2
+			continue
+		case c == '/' && len(tokens) > 0 && tokens[len(tokens)-1].text == "=~":
+			j := i + 1
+			for ; j < len(when) && when[j] != '/'; j++ {
+				if when[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(when) {
+				return nil, fmt.Errorf("unterminated regex in when clause %q", when)
+			}
+			// Include any regex flags.
+			for j++; j < len(when) && strings.IndexByte("gimsuy", when[j]) >= 0; j++ {
+			}
+			tokens = append(tokens, &whenToken{whenRegex, when[i:j]})
+			i = j
+			continue
+		}
+
+		if op := whenOperatorAt(when[i:]); op != "" {
+			tokens = append(tokens, &whenToken{whenOperator, op})
+			i += len(op)
+			continue
+		}
+
+		j := i
+		for ; j < len(when) && strings.IndexByte(" \t\n'()!&|=<>", when[j]) < 0; j++ {
+		}
+		tokens = append(tokens, &whenToken{whenWord, when[i:j]})
+		i = j
+	}
+	return tokens, nil
+}
+
+func whenOperatorAt(s string) string {
+	for _, op := range whenOperators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+type whenParser struct {
+	tokens []*whenToken
+	pos    int
+}
+
+func (wp *whenParser) done() bool {
+	return wp.pos >= len(wp.tokens)
+}
+
+func (wp *whenParser) peek() *whenToken {
+	if wp.done() {
+		return &whenToken{whenOperator, ""}
+	}
+	return wp.tokens[wp.pos]
+}
+
+func (wp *whenParser) next() *whenToken {
+	t := wp.peek()
+	wp.pos++
+	return t
+}
+
+func (wp *whenParser) or() (whenExpr, error) {
+	var parts []whenExpr
+	for {
+		p, err := wp.and()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, p)
+		if wp.peek().text != "||" {
+			break
+		}
+		wp.next()
+	}
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	return &whenOr{parts}, nil
+}
+
+func (wp *whenParser) and() (whenExpr, error) {
+	var parts []whenExpr
+	for {
+		p, err := wp.unary()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, p)
+		if wp.peek().text != "&&" {
+			break
+		}
+		wp.next()
+	}
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	return &whenAnd{parts}, nil
+}
+
+func (wp *whenParser) unary() (whenExpr, error) {
+	t := wp.next()
+	switch {
+	case t.tokenType == whenOperator && t.text == "!":
+		e, err := wp.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &whenNot{e}, nil
+	case t.tokenType == whenOperator && t.text == "(":
+		e, err := wp.or()
+		if err != nil {
+			return nil, err
+		}
+		if c := wp.next(); c.text != ")" {
+			return nil, fmt.Errorf("expected `)`; got %q", c.text)
+		}
+		return e, nil
+	case t.tokenType != whenWord:
+		return nil, fmt.Errorf("expected context key; got %q", t.text)
+	case t.text == "true" || t.text == "false":
+		return &whenConst{t.text == "true"}, nil
+	}
+
+	key := t.text
+	op := wp.peek()
+	switch {
+	case op.tokenType == whenOperator && whenComparisons[op.text] != "":
+		wp.next()
+		v := wp.next()
+		if v.tokenType == whenOperator {
+			return nil, fmt.Errorf("expected value after %q; got %q", op.text, v.text)
+		}
+		return &whenCompare{key, whenComparisons[op.text], v.text}, nil
+	case op.tokenType == whenWord && op.text == "in":
+		wp.next()
+		v := wp.next()
+		if v.tokenType != whenWord {
+			return nil, fmt.Errorf("expected context key after `in`; got %q", v.text)
+		}
+		return &whenCompare{key, "in", v.text}, nil
+	case op.tokenType == whenWord && op.text == "not" && wp.pos+1 < len(wp.tokens) && wp.tokens[wp.pos+1].text == "in":
+		wp.next()
+		wp.next()
+		v := wp.next()
+		if v.tokenType != whenWord {
+			return nil, fmt.Errorf("expected context key after `not in`; got %q", v.text)
+		}
+		return &whenCompare{key, "not in", v.text}, nil
+	}
+	return &whenKey{key}, nil
+}
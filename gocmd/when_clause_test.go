@@ -0,0 +1,235 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWhenClause(t *testing.T) {
+	ctx := whenContextValues{
+		"editorTextFocus":        true,
+		"editorReadonly":         false,
+		"groog.context.findMode": true,
+		"resourceLangId":         "go",
+		"resourceExtname":        ".go",
+		"activePanel":            "workbench.panel.terminal",
+		"groog.count":            3.0,
+	}
+
+	for _, test := range []struct {
+		when string
+		want bool
+	}{
+		{"", true},
+		{"true", true},
+		{"false", false},
+		{"editorTextFocus", true},
+		{"editorReadonly", false},
+		{"missingKey", false},
+		{"!missingKey", true},
+		{"!!editorTextFocus", true},
+		{"editorTextFocus && !editorReadonly", true},
+		{"editorTextFocus && editorReadonly", false},
+		{"editorReadonly || groog.context.findMode", true},
+		{"editorReadonly || missingKey", false},
+		// `&&` binds more tightly than `||`.
+		{"editorTextFocus || editorReadonly && missingKey", true},
+		{"(editorTextFocus || editorReadonly) && missingKey", false},
+		{"!(editorReadonly || missingKey)", true},
+		{"resourceLangId == go", true},
+		{"resourceLangId == 'go'", true},
+		{"resourceLangId === go", true},
+		{"resourceLangId == python", false},
+		{"resourceLangId != python", true},
+		{"resourceLangId !== go", false},
+		{"missingKey != python", true},
+		{"missingKey == python", false},
+		{"editorTextFocus == true", true},
+		{"groog.count == 3", true},
+		{"groog.count > 2", true},
+		{"groog.count >= 3", true},
+		{"groog.count < 3", false},
+		{"groog.count <= 2", false},
+		{"missingKey < 3", false},
+		{"resourceLangId < 3", false},
+		{"resourceExtname =~ /^\\.(go|ts)$/", true},
+		{"resourceExtname =~ /^\\.GO$/i", true},
+		{"resourceExtname =~ /^\\.GO$/", false},
+		{"activePanel =~ /terminal/ && !editorReadonly", true},
+		{"missingKey =~ /.*/", false},
+	} {
+		t.Run(test.when, func(t *testing.T) {
+			expr, err := parseWhenClause(test.when)
+			if err != nil {
+				t.Fatalf("parseWhenClause(%q) returned error: %v", test.when, err)
+			}
+			if got := expr.evaluate(ctx, func(w string) { t.Errorf("evaluate() warned: %s", w) }); got != test.want {
+				t.Errorf("parseWhenClause(%q).evaluate() returned %v; want %v", test.when, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseWhenClauseErrors(t *testing.T) {
+	for _, test := range []struct {
+		when string
+		want string
+	}{
+		{"a &&", `failed to parse when clause "a &&": expected context key; got ""`},
+		{"(a || b", "failed to parse when clause \"(a || b\": expected `)`; got \"\""},
+		{"a b", `failed to parse when clause "a b": unexpected token "b"`},
+		{"a == ", `failed to parse when clause "a == ": expected value after "=="; got ""`},
+		{"a == 'b", `unterminated string in when clause "a == 'b"`},
+		{"a =~ /b", `unterminated regex in when clause "a =~ /b"`},
+		{"a in 'b'", "failed to parse when clause \"a in 'b'\": expected context key after `in`; got \"b\""},
+		{"a not in (b)", "failed to parse when clause \"a not in (b)\": expected context key after `not in`; got \"(\""},
+	} {
+		t.Run(test.when, func(t *testing.T) {
+			_, err := parseWhenClause(test.when)
+			if err == nil || err.Error() != test.want {
+				t.Errorf("parseWhenClause(%q) returned error %v; want %q", test.when, err, test.want)
+			}
+		})
+	}
+}
+
+func TestWhenContextKeys(t *testing.T) {
+	for _, test := range []struct {
+		when string
+		want []string
+	}{
+		{"", nil},
+		{"true && !false", nil},
+		{"editorTextFocus && !editorReadonly", []string{"editorReadonly", "editorTextFocus"}},
+		{"resourceLangId == go || resourceLangId == python", []string{"resourceLangId"}},
+		{"resourceScheme in debugSchemes", []string{"debugSchemes", "resourceScheme"}},
+		{"resourceFilename not in groog.ignoredFiles", []string{"groog.ignoredFiles", "resourceFilename"}},
+		{"resourceExtname =~ /^\\.(go|ts)$/ && (a || !b)", []string{"a", "b", "resourceExtname"}},
+	} {
+		t.Run(test.when, func(t *testing.T) {
+			got, err := whenContextKeys(test.when)
+			if err != nil {
+				t.Fatalf("whenContextKeys(%q) returned error: %v", test.when, err)
+			}
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("whenContextKeys(%q) returned %v; want %v", test.when, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseWhenContextValues(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		s       string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "empty",
+			want: `{}`,
+		},
+		{
+			name: "values",
+			s:    "groog.context.findMode=true, editorReadonly=false,resourceLangId=go,count=2,editorTextFocus,",
+			want: `{"count":2,"editorReadonly":false,"editorTextFocus":true,"groog.context.findMode":true,"resourceLangId":"go"}`,
+		},
+		{
+			name: "only true and false are bools",
+			s:    "a=True,b=1",
+			want: `{"a":"True","b":1}`,
+		},
+		{
+			name:    "missing key",
+			s:       "a=1,=2",
+			wantErr: `invalid context assignment "=2"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseWhenContextValues(test.s)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("parseWhenContextValues(%q) returned error %v; want %q", test.s, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseWhenContextValues(%q) returned error: %v", test.s, err)
+			}
+			if compactJson(got) != test.want {
+				t.Errorf("parseWhenContextValues(%q) returned %s; want %s", test.s, compactJson(got), test.want)
+			}
+		})
+	}
+}
+
+func TestWhenInWarning(t *testing.T) {
+	for _, test := range []struct {
+		when string
+		want bool
+		// wantWarnings are the warnings for the evaluated `in` clauses (clauses
+		// skipped by short-circuiting aren't evaluated).
+		wantWarnings []string
+	}{
+		{
+			when:         "resourceScheme in debugSchemes",
+			want:         false,
+			wantWarnings: []string{"`resourceScheme in debugSchemes` is treated as false since array and object context values aren't supported"},
+		},
+		{
+			when:         "resourceFilename not in groog.ignoredFiles",
+			want:         true,
+			wantWarnings: []string{"`resourceFilename not in groog.ignoredFiles` is treated as true since array and object context values aren't supported"},
+		},
+		{
+			when: "editorReadonly && resourceScheme in debugSchemes",
+			want: false,
+		},
+	} {
+		t.Run(test.when, func(t *testing.T) {
+			expr, err := parseWhenClause(test.when)
+			if err != nil {
+				t.Fatalf("parseWhenClause(%q) returned error: %v", test.when, err)
+			}
+
+			var warnings []string
+			got := expr.evaluate(whenContextValues{}, func(w string) { warnings = append(warnings, w) })
+			if got != test.want {
+				t.Errorf("parseWhenClause(%q).evaluate() returned %v; want %v", test.when, got, test.want)
+			}
+			if strings.Join(warnings, "\n") != strings.Join(test.wantWarnings, "\n") {
+				t.Errorf("parseWhenClause(%q).evaluate() warned %q; want %q", test.when, warnings, test.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestNormalizeWhenClause(t *testing.T) {
+	for _, test := range []struct {
+		when string
+		want string
+	}{
+		{"", "true"},
+		{"editorTextFocus", "editorTextFocus"},
+		{"  editorTextFocus&&!editorReadonly ", "editorTextFocus && !editorReadonly"},
+		{"(a && b) && c", "a && b && c"},
+		{"a && (b && c)", "a && b && c"},
+		{"(a || b) || c", "a || b || c"},
+		{"a || (b && c)", "a || b && c"},
+		{"(a || b) && c", "(a || b) && c"},
+		{"!(a || b)", "!(a || b)"},
+		{"!(a)", "!a"},
+		{"!(a == b)", "!(a == b)"},
+		{"resourceLangId === 'go'", "resourceLangId == go"},
+		{"resourceFilename =~ /^.*\\.go$/", "resourceFilename =~ /^.*\\.go$/"},
+		{"a not in b", "a not in b"},
+		// Invalid when clauses are returned as is.
+		{"a && (b", "a && (b"},
+	} {
+		t.Run(test.when, func(t *testing.T) {
+			if got := normalizeWhenClause(test.when); got != test.want {
+				t.Errorf("normalizeWhenClause(%q) returned %q; want %q", test.when, got, test.want)
+			}
+		})
+	}
+}
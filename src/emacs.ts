@@ -196,8 +196,8 @@ export class Emacs {
       }
     }));
 
-    this.recorder.registerCommand(context, 'jump', (jd: JumpDist | undefined) => this.jump(jd || defaultJumpDist));
-    this.recorder.registerCommand(context, 'fall', (jd: JumpDist | undefined) => this.fall(jd || defaultJumpDist));
+    this.recorder.registerCommand(context, 'jump', (jd: JumpDist | undefined) => this.jump(jd));
+    this.recorder.registerCommand(context, 'fall', (jd: JumpDist | undefined) => this.fall(jd));
     this.recorder.registerCommand(context, 'format', () => this.format());
 
     this.recorder.registerCommand(context, 'toggleQMK', () => this.qmkTracker.toggle(context));
@@ -444,13 +444,13 @@ export class Emacs {
   }
 
   // C-l
-  async jump(jd: JumpDist) {
-    await this.move(CursorMove.Move, { "to": "up", "by": "line", "value": jd.lines });
+  async jump(jd?: JumpDist) {
+    await this.move(CursorMove.Move, { "to": "up", "by": "line", "value": jumpLines(jd) });
   }
 
   // C-v
-  async fall(jd: JumpDist) {
-    await this.move(CursorMove.Move, { "to": "down", "by": "line", "value": jd.lines });
+  async fall(jd?: JumpDist) {
+    await this.move(CursorMove.Move, { "to": "down", "by": "line", "value": jumpLines(jd) });
   }
 
   async move(vsCommand: CursorMove, ...rest: any[]): Promise<void> {
@@ -483,12 +483,18 @@ interface TypeArg {
 }
 
 interface JumpDist {
-  lines: number;
+  lines?: number;
+  // If set, jump by the number of lines in the groog.jump.superJumpLines setting.
+  superJump?: boolean;
 }
 
-const defaultJumpDist: JumpDist = {
-  lines: 10,
-};
+function jumpLines(jd?: JumpDist): number {
+  if (jd?.lines) {
+    return jd.lines;
+  }
+  const config = vscode.workspace.getConfiguration("groog.jump");
+  return jd?.superJump ? config.get<number>("superJumpLines", 50) : config.get<number>("lines", 10);
+}
 
 function isFileUri(uri: vscode.Uri): boolean {
   return FILE_ISH_SCHEMES.includes(uri.scheme);
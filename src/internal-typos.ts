@@ -1,7 +1,7 @@
 // Used https://github.com/genesy/auto-correct as guidance for this logic.
 import * as vscode from 'vscode';
 import { WordSeparatorSetting } from './settings';
-import { Correction, defaultCorrections, globalLanguageKey } from './typos';
+import { Correction, globalLanguageKey } from './typos';
 
 const whitespaceCharBreakKey = "WHITESPACE";
 
@@ -34,11 +34,16 @@ const lastWordPattern = /\b([a-zA-Z]+)$/;
 export class TypoFixer {
   // map from language to char to correction options
   perLanguageCorrections: InternalCorrectorsByLanguage;
+  // Corrections configured in language-scoped settings (populated lazily per language).
+  languageScopedCorrections: InternalCorrectorsByLanguage;
   globalCorrections: InternalCorrector;
   defaultBreakCharacters: string;
+  includeDefaults: boolean;
 
   constructor() {
     this.perLanguageCorrections = {};
+    this.languageScopedCorrections = {};
+    this.includeDefaults = true;
     this.globalCorrections = {};
     this.defaultBreakCharacters = "";
     this.reload();
@@ -54,7 +59,10 @@ export class TypoFixer {
 
   public reload(silent?: boolean) : void {
     const config = vscode.workspace.getConfiguration("groog");
-    const corrections = config.get<Correction[]>("typos");
+    // The built-in corrections are contributed as the default value (see gocmd/default_typos.json),
+    // so inspect the setting to apply user-configured corrections in addition to the defaults.
+    const inspect = config.inspect<Correction[]>("typos");
+    const userCorrections = inspect?.workspaceFolderValue ?? inspect?.workspaceValue ?? inspect?.globalValue ?? [];
 
     const [_, separators] = WordSeparatorSetting.getWordSeparators();
     if (!separators) {
@@ -66,7 +74,10 @@ export class TypoFixer {
       this.defaultBreakCharacters = separators;
     }
 
-    this.perLanguageCorrections = this.externalTypoToInternal((corrections || []).concat(defaultCorrections()));
+    this.includeDefaults = config.get<boolean>("includeDefaultTypos", true);
+    this.languageScopedCorrections = {};
+    const builtInCorrections = (this.includeDefaults ? inspect?.defaultValue : undefined) ?? [];
+    this.perLanguageCorrections = this.externalTypoToInternal(userCorrections.concat(builtInCorrections));
     this.globalCorrections = this.perLanguageCorrections[globalLanguageKey];
   }
 
@@ -94,7 +105,12 @@ export class TypoFixer {
     }
     const [word, lastWordRange] : [string, vscode.Range] = lastWordData;
 
-    // First run the language specific correction.
+    // First run the corrections from language-scoped settings.
+    if ((await this.runCorrection(this.languageScopedCorrector(editor.document.languageId), editor, char, word, lastWordRange))) {
+      return true;
+    }
+
+    // Then run the language specific correction.
     if (editor.document.languageId in this.perLanguageCorrections) {
       const byBreakChar = this.perLanguageCorrections[editor.document.languageId];
       if ((await this.runCorrection(byBreakChar, editor, char, word, lastWordRange))) {
@@ -155,6 +171,19 @@ export class TypoFixer {
     return true;
   }
 
+  languageScopedCorrector(languageId: string): InternalCorrector {
+    if (!(languageId in this.languageScopedCorrections)) {
+      const inspect = vscode.workspace.getConfiguration("groog", { languageId }).inspect<Correction[]>("typos");
+      const userCorrections = inspect?.workspaceFolderLanguageValue ?? inspect?.workspaceLanguageValue ?? inspect?.globalLanguageValue ?? [];
+      const builtInCorrections = (this.includeDefaults ? inspect?.defaultLanguageValue : undefined) ?? [];
+      // Defaults go first so user-configured corrections take precedence.
+      // The language is implied by the setting scope.
+      const langCorrections = builtInCorrections.concat(userCorrections).map(c => ({ ...c, languages: undefined }));
+      this.languageScopedCorrections[languageId] = this.externalTypoToInternal(langCorrections)[globalLanguageKey] || {};
+    }
+    return this.languageScopedCorrections[languageId];
+  }
+
   getOptions(byBreakChar : BreakCharToOptions, breakCharacter : string) : CorrectionOptions | undefined {
     if (breakCharacter in byBreakChar) {
       return byBreakChar[breakCharacter];
@@ -57,6 +57,10 @@ export const miscCommands: MiscCommand[] = [
     name: "testFile",
     f: (e: Emacs, mc: TestFileArgs) => testFile(mc, e.lastVisitedFile),
   },
+  {
+    name: "quickOpen.page",
+    f: (e: Emacs, args: QuickOpenPageArgs) => quickOpenPage(args),
+  },
   {
     name: "trimClipboard",
     f: trimClipboard,
@@ -93,6 +97,9 @@ interface SingleCommand {
   args?: any;
   async?: boolean;
   delay?: number;
+  // The groog setting that contains the delay (in milliseconds) to use.
+  // This takes precedence over the delay field.
+  delaySetting?: string;
 }
 
 interface MultiCommand {
@@ -107,8 +114,9 @@ export async function multiCommand(mc: MultiCommand) {
     // sc.args if it's undefined.
     const func = sc.args === undefined ? () => vscode.commands.executeCommand(sc.command) : () => vscode.commands.executeCommand(sc.command, sc.args);
 
-    if (sc.delay) {
-      setTimeout(func, sc.delay);
+    const delay = sc.delaySetting ? vscode.workspace.getConfiguration("groog").get<number>(sc.delaySetting) : sc.delay;
+    if (delay) {
+      setTimeout(func, delay);
     } else if (sc.async) {
       func()
     } else {
@@ -169,6 +177,18 @@ async function testFile(args: TestFileArgs, lastFile?: vscode.Uri) {
   await getLanguageSpec(file).testingBehavior(args, file);
 }
 
+interface QuickOpenPageArgs {
+  previous?: boolean;
+}
+
+async function quickOpenPage(args: QuickOpenPageArgs | undefined) {
+  const pageSize = vscode.workspace.getConfiguration("groog.quickOpen").get<number>("pageSize", 5);
+  const command = args?.previous ? "workbench.action.quickOpenNavigatePreviousInFilePicker" : "workbench.action.quickOpenNavigateNextInFilePicker";
+  for (let i = 0; i < pageSize; i++) {
+    await vscode.commands.executeCommand(command);
+  }
+}
+
 interface Message {
   message: string;
   error?: boolean;